@@ -7,7 +7,8 @@ import (
 	"unicode/utf8"
 )
 
-var replacer = strings.NewReplacer(" ", "", "-", "")
+// DefaultIgnored are the characters ignored in words and queries by default: spaces and hyphens
+const DefaultIgnored = " -"
 
 // Tree is an anagram tree
 type Tree struct {
@@ -15,6 +16,7 @@ type Tree struct {
 	Leaves     []Leaf
 	Letters    []rune
 	LettersMap map[rune]int
+	ignored    *strings.Replacer
 }
 
 // Node is a node in an AnagramTree
@@ -24,28 +26,90 @@ type Node struct {
 	Leaf     int
 }
 
-// Leaf is a tree leaf
-type Leaf []string
+// Leaf is a tree leaf, holding words consisting of the same letters
+type Leaf struct {
+	Words []string
+	// Frequencies holds the frequencies of the words. Nil if the words were added without frequencies
+	Frequencies []int
+}
 
-// NewTree creates a new Tree
+// Frequency returns the frequency of the word at the given index. Returns 0 for leaves without frequencies
+func (l Leaf) Frequency(index int) int {
+	if l.Frequencies == nil {
+		return 0
+	}
+	return l.Frequencies[index]
+}
+
+// NewTree creates a new Tree. Upper- and lower-case letters are treated as the same letter
 func NewTree(letters []rune) Tree {
 	lettersMap := make(map[rune]int, 2*len(letters))
 	for i, letter := range letters {
 		lettersMap[letter] = i
 		lettersMap[unicode.ToUpper(letter)] = i
 	}
+	return newTree(letters, lettersMap)
+}
+
+// NewCaseSensitiveTree creates a new Tree that distinguishes upper- and lower-case letters.
+// The upper-case forms of the letters are used as additional letters, before the given letters.
+func NewCaseSensitiveTree(letters []rune) Tree {
+	all := make([]rune, 0, 2*len(letters))
+	for _, letter := range letters {
+		if upper := unicode.ToUpper(letter); upper != letter {
+			all = append(all, upper)
+		}
+	}
+	all = append(all, letters...)
+
+	lettersMap := make(map[rune]int, len(all))
+	for i, letter := range all {
+		lettersMap[letter] = i
+	}
+	return newTree(all, lettersMap)
+}
+
+func newTree(letters []rune, lettersMap map[rune]int) Tree {
 	root := NewNode(letters[0], -1)
 	return Tree{
 		Root:       &root,
 		Leaves:     make([]Leaf, 0, 0),
 		Letters:    letters,
 		LettersMap: lettersMap,
+		ignored:    newIgnoredReplacer(DefaultIgnored),
+	}
+}
+
+func newIgnoredReplacer(chars string) *strings.Replacer {
+	pairs := make([]string, 0, 2*len(chars))
+	for _, char := range chars {
+		pairs = append(pairs, string(char), "")
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// SetIgnored sets the characters that are ignored in words and queries, like spaces and hyphens.
+// Words are stored in their original form. Must be called before adding words.
+func (t *Tree) SetIgnored(chars string) {
+	t.ignored = newIgnoredReplacer(chars)
+}
+
+// Strip removes the ignored characters from a word
+func (t *Tree) Strip(word string) string {
+	if t.ignored == nil {
+		return word
 	}
+	return t.ignored.Replace(word)
+}
+
+// length returns the number of characters of a word, without ignored characters
+func (t *Tree) length(word string) int {
+	return utf8.RuneCountInString(t.Strip(word))
 }
 
 // Anagrams finds full anagrams
 func (t *Tree) Anagrams(word string) Leaf {
-	word = replacer.Replace(word)
+	word = t.Strip(word)
 
 	hist := make([]int, len(t.Letters), len(t.Letters))
 	Histogram(word, t.LettersMap, false, hist)
@@ -70,7 +134,7 @@ func (t *Tree) anagrams(hist []int) (int, bool) {
 
 // AnagramsWithUnknown finds full anagrams
 func (t *Tree) AnagramsWithUnknown(word string, minUnknown, maxUnknown uint) []Leaf {
-	word = replacer.Replace(word)
+	word = t.Strip(word)
 
 	hist := make([]int, len(t.Letters), len(t.Letters))
 	Histogram(word, t.LettersMap, false, hist)
@@ -127,11 +191,31 @@ func (t *Tree) anagramsWithUnknown(hist []int, minUnknown, maxUnknown uint) []in
 
 // PartialAnagrams finds partial anagrams
 func (t *Tree) PartialAnagrams(word string, minLength uint) []Leaf {
-	word = replacer.Replace(word)
+	word = t.Strip(word)
+
+	hist := make([]int, len(t.Letters), len(t.Letters))
+	Histogram(word, t.LettersMap, false, hist)
+
+	indices := t.partialAnagrams(hist, minLength)
+	results := make([]Leaf, len(indices), len(indices))
+	for i, idx := range indices {
+		results[i] = t.Leaves[idx]
+	}
+
+	return results
+}
+
+// Histogram returns the histogram of letter counts of a word, in the order of the tree's letters
+func (t *Tree) Histogram(word string) []int {
+	word = t.Strip(word)
 
 	hist := make([]int, len(t.Letters), len(t.Letters))
 	Histogram(word, t.LettersMap, false, hist)
+	return hist
+}
 
+// PartialAnagramsHist finds partial anagrams of a histogram of letter counts. See Histogram
+func (t *Tree) PartialAnagramsHist(hist []int, minLength uint) []Leaf {
 	indices := t.partialAnagrams(hist, minLength)
 	results := make([]Leaf, len(indices), len(indices))
 	for i, idx := range indices {
@@ -163,7 +247,7 @@ func (t *Tree) partialAnagrams(hist []int, minLength uint) []int {
 	}
 
 	for _, o := range open {
-		if minLength == 0 || utf8.RuneCountInString(t.Leaves[o.Leaf][0]) >= int(minLength) {
+		if minLength == 0 || t.length(t.Leaves[o.Leaf].Words[0]) >= int(minLength) {
 			results = append(results, o.Leaf)
 		}
 	}
@@ -173,7 +257,7 @@ func (t *Tree) partialAnagrams(hist []int, minLength uint) []int {
 
 // PartialAnagramsWithUnknown finds partial anagrams
 func (t *Tree) PartialAnagramsWithUnknown(word string, minLength, minUnknown, maxUnknown uint) []Leaf {
-	word = replacer.Replace(word)
+	word = t.Strip(word)
 
 	hist := make([]int, len(t.Letters), len(t.Letters))
 	Histogram(word, t.LettersMap, false, hist)
@@ -221,7 +305,7 @@ func (t *Tree) partialAnagramsWithUnknown(hist []int, minLength, minUnknown, max
 	diff := maxUnknown - minUnknown
 	for _, o := range open {
 		if o.Unknowns <= diff &&
-			(minLength == 0 || utf8.RuneCountInString(t.Leaves[o.Node.Leaf][0]) >= int(minLength)) {
+			(minLength == 0 || t.length(t.Leaves[o.Node.Leaf].Words[0]) >= int(minLength)) {
 
 			results = append(results, o.Node.Leaf)
 		}
@@ -232,7 +316,7 @@ func (t *Tree) partialAnagramsWithUnknown(hist []int, minLength, minUnknown, max
 
 // MultiAnagrams finds combinations of partial anagrams
 func (t *Tree) MultiAnagrams(word string, maxWords, minLength uint, permutations bool) [][]Leaf {
-	word = replacer.Replace(word)
+	word = t.Strip(word)
 
 	hist := make([]int, len(t.Letters), len(t.Letters))
 	Histogram(word, t.LettersMap, false, hist)
@@ -259,16 +343,17 @@ func (t *Tree) multiAnagrams(hist []int, maxWords, minLength uint, permutations
 
 	partials := t.partialAnagrams(hist, minLength)
 
-	tree := NewTree(t.Letters)
+	tree := newTree(t.Letters, t.LettersMap)
+	tree.ignored = t.ignored
 	for _, p := range partials {
-		tree.AddWords(t.Leaves[p], nil)
+		tree.AddWordsFrequencies(t.Leaves[p].Words, t.Leaves[p].Frequencies, nil)
 	}
 
 	open := [][]int{}
 	closed := [][]int{}
 
 	for i, p := range tree.Leaves {
-		if utf8.RuneCountInString(p[0]) == totalLen {
+		if t.length(p.Words[0]) == totalLen {
 			closed = append(closed, []int{i})
 		} else {
 			open = append(open, []int{i})
@@ -290,7 +375,7 @@ func (t *Tree) multiAnagrams(hist []int, maxWords, minLength uint, permutations
 
 		strLen := 0
 		for _, c := range curr {
-			str := tree.Leaves[c][0]
+			str := t.Strip(tree.Leaves[c].Words[0])
 			strLen += utf8.RuneCountInString(str)
 			Histogram(str, t.LettersMap, true, tempHist)
 		}
@@ -309,8 +394,8 @@ func (t *Tree) multiAnagrams(hist []int, maxWords, minLength uint, permutations
 			new = append(new, curr...)
 			new = append(new, sub)
 
-			str := tree.Leaves[sub][0]
-			if strLen+utf8.RuneCountInString(str) == totalLen {
+			str := tree.Leaves[sub].Words[0]
+			if strLen+t.length(str) == totalLen {
 				closed = append(closed, new)
 			} else {
 				if maxWords <= 0 || len(new) < int(maxWords) {
@@ -325,6 +410,12 @@ func (t *Tree) multiAnagrams(hist []int, maxWords, minLength uint, permutations
 
 // AddWords adds words to the tree
 func (t *Tree) AddWords(words []string, progress chan int) {
+	t.AddWordsFrequencies(words, nil, progress)
+}
+
+// AddWordsFrequencies adds words to the tree, like AddWords.
+// Additionally, stores the frequencies of the words in the leaves. Frequencies are optional, and in the order of the words
+func (t *Tree) AddWordsFrequencies(words []string, freqs []int, progress chan int) {
 	if progress != nil {
 		defer close(progress)
 	}
@@ -335,14 +426,15 @@ func (t *Tree) AddWords(words []string, progress chan int) {
 	result := make([]int, len(t.Letters), len(t.Letters))
 
 	for w, word := range words {
-		if len(word) == 0 {
+		stripped := t.Strip(word)
+		if len(stripped) == 0 {
 			continue
 		}
 
 		for i := 0; i < len(result); i++ {
 			result[i] = 0
 		}
-		Histogram(word, t.LettersMap, false, result)
+		Histogram(stripped, t.LettersMap, false, result)
 
 		node := t.Root
 		for i, cnt := range result {
@@ -368,7 +460,11 @@ func (t *Tree) AddWords(words []string, progress chan int) {
 				}
 			}
 			if i == len(result)-1 {
-				t.Leaves[node.Leaf] = append(t.Leaves[node.Leaf], word)
+				leaf := &t.Leaves[node.Leaf]
+				leaf.Words = append(leaf.Words, word)
+				if freqs != nil {
+					leaf.Frequencies = append(leaf.Frequencies, freqs[w])
+				}
 			}
 		}
 
@@ -384,7 +480,7 @@ func (t *Tree) AddWords(words []string, progress chan int) {
 }
 
 func (t *Tree) addLeaf() int {
-	t.Leaves = append(t.Leaves, []string{})
+	t.Leaves = append(t.Leaves, Leaf{Words: []string{}})
 	return len(t.Leaves) - 1
 }
 
@@ -22,29 +22,89 @@ func TestTree(t *testing.T) {
 	}
 
 	ana := tree.Anagrams("abc")
-	assert.Equal(t, Leaf{"abc", "bca", "cab"}, ana, "Wrong anagrams")
+	assert.Equal(t, Leaf{Words: []string{"abc", "bca", "cab"}}, ana, "Wrong anagrams")
 
 	anaUk := tree.AnagramsWithUnknown("abc", 0, 0)
-	assert.Equal(t, []Leaf{{"abc", "bca", "cab"}}, anaUk, "Wrong anagrams")
+	assert.Equal(t, []Leaf{{Words: []string{"abc", "bca", "cab"}}}, anaUk, "Wrong anagrams")
 
 	anaUk = tree.AnagramsWithUnknown("abc", 0, 1)
-	assert.Equal(t, []Leaf{{"abc", "bca", "cab"}}, anaUk, "Wrong anagrams")
+	assert.Equal(t, []Leaf{{Words: []string{"abc", "bca", "cab"}}}, anaUk, "Wrong anagrams")
 
 	anaUk = tree.AnagramsWithUnknown("abc", 0, 3)
-	assert.Equal(t, []Leaf{{"abc", "bca", "cab"}, {"abcdef", "fedcba"}}, anaUk, "Wrong anagrams")
+	assert.Equal(t, []Leaf{{Words: []string{"abc", "bca", "cab"}}, {Words: []string{"abcdef", "fedcba"}}}, anaUk, "Wrong anagrams")
 
 	anaPt := tree.PartialAnagrams("abcdef", 0)
-	assert.Equal(t, []Leaf{{"abc", "bca", "cab"}, {"abcdef", "fedcba"}}, anaPt, "Wrong anagrams")
+	assert.Equal(t, []Leaf{{Words: []string{"abc", "bca", "cab"}}, {Words: []string{"abcdef", "fedcba"}}}, anaPt, "Wrong anagrams")
 
 	anaPt = tree.PartialAnagrams("abcdef", 4)
-	assert.Equal(t, []Leaf{{"abcdef", "fedcba"}}, anaPt, "Wrong anagrams")
+	assert.Equal(t, []Leaf{{Words: []string{"abcdef", "fedcba"}}}, anaPt, "Wrong anagrams")
 
 	anaPt = tree.PartialAnagramsWithUnknown("abcd", 0, 0, 0)
-	assert.Equal(t, []Leaf{{"abc", "bca", "cab"}}, anaPt, "Wrong anagrams")
+	assert.Equal(t, []Leaf{{Words: []string{"abc", "bca", "cab"}}}, anaPt, "Wrong anagrams")
 
 	anaPt = tree.PartialAnagramsWithUnknown("abcd", 0, 0, 3)
-	assert.Equal(t, []Leaf{{"abc", "bca", "cab"}, {"abcdef", "fedcba"}}, anaPt, "Wrong anagrams")
+	assert.Equal(t, []Leaf{{Words: []string{"abc", "bca", "cab"}}, {Words: []string{"abcdef", "fedcba"}}}, anaPt, "Wrong anagrams")
+
+	hist := tree.Histogram("ab-c def")
+	anaPt = tree.PartialAnagramsHist(hist, 0)
+	assert.Equal(t, []Leaf{{Words: []string{"abc", "bca", "cab"}}, {Words: []string{"abcdef", "fedcba"}}}, anaPt, "Wrong anagrams")
 
 	anaMult := tree.MultiAnagrams("abcabc", 0, 0, false)
-	assert.Equal(t, [][]Leaf{{{"abc", "bca", "cab"}, {"abc", "bca", "cab"}}}, anaMult, "Wrong anagrams")
+	assert.Equal(t, [][]Leaf{{{Words: []string{"abc", "bca", "cab"}}, {Words: []string{"abc", "bca", "cab"}}}}, anaMult, "Wrong anagrams")
+}
+
+func TestCaseSensitiveTree(t *testing.T) {
+	tree := NewCaseSensitiveTree([]rune(Letters))
+	tree.AddWords([]string{"Polish", "polish", "Lipsoh"}, nil)
+
+	assert.Equal(t, Leaf{Words: []string{"polish"}}, tree.Anagrams("polish"), "Wrong lower-case anagrams")
+	assert.Equal(t, Leaf{Words: []string{"Polish"}}, tree.Anagrams("Polish"), "Wrong upper-case anagrams")
+	assert.Equal(t, Leaf{Words: []string{"Lipsoh"}}, tree.Anagrams("hopsiL"), "Wrong upper-case anagrams")
+
+	anaMult := tree.MultiAnagrams("PolishLipsoh", 0, 0, false)
+	assert.Equal(t, [][]Leaf{{{Words: []string{"Lipsoh"}}, {Words: []string{"Polish"}}}}, anaMult, "Wrong multi-anagrams")
+
+	tree = NewTree([]rune(Letters))
+	tree.AddWords([]string{"Polish", "polish"}, nil)
+	assert.Equal(t, Leaf{Words: []string{"Polish", "polish"}}, tree.Anagrams("POLISH"), "Wrong case-insensitive anagrams")
+}
+
+func TestTreeFrequencies(t *testing.T) {
+	tree := NewTree([]rune(Letters))
+	tree.AddWordsFrequencies([]string{"abc", "bca", "-", "cab", "ab"}, []int{0, 10, 3, 5, 2}, nil)
+
+	assert.Equal(t, Leaf{Words: []string{"abc", "bca", "cab"}, Frequencies: []int{0, 10, 5}}, tree.Anagrams("abc"), "Wrong anagrams")
+	assert.Equal(t, 10, tree.Anagrams("abc").Frequency(1), "Wrong frequency")
+
+	anaMult := tree.MultiAnagrams("abcab", 0, 0, false)
+	assert.Equal(t, [][]Leaf{{{Words: []string{"ab"}, Frequencies: []int{2}}, {Words: []string{"abc", "bca", "cab"}, Frequencies: []int{0, 10, 5}}}}, anaMult, "Wrong multi-anagrams")
+
+	tree = NewTree([]rune(Letters))
+	tree.AddWords([]string{"abc"}, nil)
+	assert.Equal(t, 0, tree.Anagrams("abc").Frequency(0), "Wrong frequency without frequencies")
+}
+
+func TestTreeIgnored(t *testing.T) {
+	tree := NewTree([]rune(Letters))
+	tree.AddWords([]string{"ice cream", "mother-in-law", "o'clock", "-", "cinema", "cream"}, nil)
+
+	assert.Equal(t, Leaf{Words: []string{"ice cream"}}, tree.Anagrams("ceramic e"), "Wrong anagrams of multi-word entry")
+	assert.Equal(t, Leaf{Words: []string{"ice cream"}}, tree.Anagrams("a merc-ice"), "Wrong anagrams of multi-word entry")
+	assert.Equal(t, Leaf{Words: []string{"mother-in-law"}}, tree.Anagrams("woman hitler"), "Wrong anagrams of hyphenated entry")
+	assert.Equal(t, Leaf{}, tree.Anagrams("clocko"), "Apostrophes should not be ignored by default")
+	assert.Equal(t, Leaf{}, tree.Anagrams(""), "Entries of only ignored characters should be skipped")
+
+	anaPt := tree.PartialAnagrams("ice cream", 6)
+	assert.Equal(t, []Leaf{{Words: []string{"ice cream"}}}, anaPt, "Wrong partial anagrams")
+
+	anaMult := tree.MultiAnagrams("ice cream cinema", 0, 6, false)
+	assert.Equal(t, [][]Leaf{{{Words: []string{"cinema"}}, {Words: []string{"ice cream"}}}}, anaMult, "Wrong multi-anagrams")
+
+	tree = NewTree([]rune(Letters))
+	tree.SetIgnored(" -'.")
+	tree.AddWords([]string{"o'clock", "st. louis"}, nil)
+
+	assert.Equal(t, "oclock", tree.Strip("o'clock"), "Wrong stripped word")
+	assert.Equal(t, Leaf{Words: []string{"o'clock"}}, tree.Anagrams("clocko"), "Wrong anagrams with ignored apostrophes")
+	assert.Equal(t, Leaf{Words: []string{"st. louis"}}, tree.Anagrams("lotus is"), "Wrong anagrams with ignored periods")
 }
@@ -1,15 +1,14 @@
 package cli
 
 import (
-	"bufio"
 	"fmt"
-	"os"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
 
-	"github.com/mlange-42/xwrd/anagram"
 	"github.com/mlange-42/xwrd/core"
+	"github.com/mlange-42/xwrd/engine"
 	"github.com/mlange-42/xwrd/util"
 	"github.com/spf13/cobra"
 )
@@ -24,11 +23,51 @@ type anagramOptions struct {
 	unknown    []uint
 	minUnknown uint
 	maxUnknown uint
+	frequencyOptions
+	caseOptions
+}
+
+// sortFreq is the sort order for the most frequent words first
+const sortFreq = "freq"
+
+// frequencyOptions are the options for filtering and sorting results by word frequency
+type frequencyOptions struct {
+	sort    string
+	minFreq int
+}
+
+// caseOptions are the options for case-sensitive queries and for proper nouns
+type caseOptions struct {
+	caseSensitive   bool
+	noProperNouns   bool
+	onlyProperNouns bool
+}
+
+// ignoreOptions are the options for characters ignored in anagrams, like spaces and hyphens
+type ignoreOptions struct {
+	names []string
+}
+
+// interactiveCommands are the flags that can be changed in interactive mode
+var interactiveCommands = map[string]bool{
+	"filter":     true,
+	"max-words":  true,
+	"min-length": true,
+	"unknown":    true,
+	"sort":       true,
+	"min-freq":   true,
+	"f":          true,
+	"w":          true,
+	"l":          true,
+	"u":          true,
 }
 
 func anagramCommand(config *core.Config) *cobra.Command {
 	op := anagramOptions{}
-	var dict string
+	var dicts []string
+	var load filterOptions
+	var ignore ignoreOptions
+	var showSources bool
 
 	anagram := &cobra.Command{
 		Use:   "anagram [WORDS...]",
@@ -41,87 +80,87 @@ Enters interactive mode if called without position arguments (i.e. words).
 `,
 		Aliases: []string{"a"},
 		Args:    util.WrappedArgs(cobra.ArbitraryArgs),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if op.partial && op.multi {
+				return util.UsageError(cmd, fmt.Errorf("flags --partial and --multi are mutually exclusive"))
+			}
 			if !op.multi && cmd.Flags().Changed("max-words") {
-				fmt.Print("ERROR: flag --max-words is only supported with flag --multi")
-				return
+				return util.UsageError(cmd, fmt.Errorf("flag --max-words is only supported with flag --multi"))
 			}
 			if !op.multi && !op.partial && op.minLength > 0 {
-				fmt.Print("ERROR: flag --min-length is only supported with flag --multi or --partial")
-				return
+				return util.UsageError(cmd, fmt.Errorf("flag --min-length is only supported with flag --multi or --partial"))
 			}
 
 			var err error
 			op.minUnknown, op.maxUnknown, err = parseUnknown(op.unknown, op.multi)
 			if err != nil {
-				fmt.Printf("ERROR: %s", err.Error())
+				return util.UsageError(cmd, err)
 			}
-
-			dictionary := config.GetDict()
-			if dict != "" {
-				dictionary = util.NewDict(dict)
+			if err = op.frequencyOptions.validate(); err != nil {
+				return util.UsageError(cmd, err)
+			}
+			if err = op.caseOptions.validate(); err != nil {
+				return util.UsageError(cmd, err)
 			}
-			words, err := util.LoadDictionary(dictionary)
+			loadFilter, err := load.parse()
 			if err != nil {
-				fmt.Printf("failed to find anagrams: %s", err.Error())
-				return
+				return util.UsageError(cmd, err)
 			}
-
-			tree := anagram.NewTree([]rune(anagram.Letters))
-			progress := make(chan int, 8)
-			go tree.AddWords(words, progress)
-
-			for pr := range progress {
-				bar := strings.Repeat("#", pr/2)
-				fmt.Fprintf(os.Stderr, "\rBuilding tree: [%-50s]", bar)
+			ignored, err := ignore.characters(config)
+			if err != nil {
+				return util.UsageError(cmd, err)
 			}
-			fmt.Fprintln(os.Stderr)
-
-			interactive := len(args) == 0
 
 			if op.filter != "" {
-				op.pattern, err = createPattern(op.filter)
+				op.pattern, err = op.compile(op.filter)
 				if err != nil {
-					fmt.Printf("failed to find anagrams: %s", err.Error())
-					return
+					return fmt.Errorf("failed to find anagrams: %w", err)
 				}
 			}
 
-			commands := map[string]bool{
-				"filter":     true,
-				"max-words":  true,
-				"min-length": true,
-				"unknown":    true,
-				"f":          true,
-				"w":          true,
-				"l":          true,
-				"u":          true,
+			words, sources, freqs, err := loadWords(config, dicts, loadFilter)
+			if err != nil {
+				return fmt.Errorf("failed to find anagrams: %w", err)
+			}
+			if !showSources {
+				sources = nil
 			}
 
+			out := cmd.OutOrStdout()
+
+			eng := engine.NewFromWords(words, op.caseOptions.engineOptions(engine.Options{Progress: progressBar(cmd.ErrOrStderr()), Frequencies: freqs, Ignored: ignored}))
+			eng.Build()
+
+			interactive := len(args) == 0
+
 			if interactive {
 				if op.multi {
-					fmt.Print("Find multi-word anagrams.")
+					fmt.Fprint(out, "Find multi-word anagrams.")
 				} else if op.partial {
-					fmt.Print("Find partial anagrams.")
+					fmt.Fprint(out, "Find partial anagrams.")
 				} else {
-					fmt.Print("Find anagrams.")
+					fmt.Fprint(out, "Find anagrams.")
 				}
-				fmt.Println(" Enter ? for help.")
+				fmt.Fprintln(out, " Enter ? for help.")
+			}
+
+			var reader lineReader
+			if interactive {
+				comp := completer{modes: []string{"#normal", "#partial", "#multi"}, flags: interactiveCommands}
+				reader = newLineReader(cmd, config.Root, comp.complete)
+				defer reader.Close()
 			}
+
+			found := 0
 			for {
 				var text []string
 				if interactive {
-					fmt.Print("Enter a word: ")
-					var answer string
-					scanner := bufio.NewScanner(os.Stdin)
-					if scanner.Scan() {
-						answer = scanner.Text()
-					}
-					if len(answer) == 0 {
+					answer, err := reader.Prompt("Enter a word: ")
+					if err != nil || len(answer) == 0 {
 						break
 					}
-					if str, ok := interactiveFlags(answer, &op, commands); ok {
-						fmt.Println(str)
+					if str, ok := interactiveFlags(answer, &op, interactiveCommands); ok {
+						fmt.Fprintln(out, str)
 						continue
 					}
 					text = []string{answer}
@@ -131,14 +170,14 @@ Enters interactive mode if called without position arguments (i.e. words).
 
 				for _, word := range text {
 					if !interactive {
-						fmt.Printf("%s:\n", word)
+						fmt.Fprintf(out, "%s:\n", word)
 					}
-					if op.partial {
-						printPartial(word, &tree, op)
-					} else if op.multi {
-						printMulti(word, &tree, op)
+					if op.multi {
+						found += printMulti(out, eng.MultiAnagrams(word, op.query()), sources)
+					} else if op.partial {
+						found += printAnagrams(out, eng.PartialAnagrams(word, op.query()), sources)
 					} else {
-						printNormal(word, &tree, op)
+						found += printAnagrams(out, eng.Anagrams(word, op.query()), sources)
 					}
 				}
 
@@ -146,9 +185,15 @@ Enters interactive mode if called without position arguments (i.e. words).
 					break
 				}
 			}
+
+			if !interactive && found == 0 {
+				return ErrNoResults
+			}
+			return nil
 		},
 	}
-	anagram.Flags().StringVarP(&dict, "dict", "d", "", "Path to the dictionary/word list to use.")
+	anagram.Flags().StringSliceVarP(&dicts, "dict", "d", []string{}, "Dictionaries to use, comma-separated or repeated. Prefix groups with '@'.")
+	anagram.Flags().BoolVar(&showSources, "sources", false, "Show the source dictionaries of words.")
 
 	anagram.Flags().BoolVarP(&op.partial, "partial", "p", false, "Find partial anagrams.")
 	anagram.Flags().BoolVarP(&op.multi, "multi", "m", false, "Find combinations of multiple partial anagrams.")
@@ -159,8 +204,10 @@ Enters interactive mode if called without position arguments (i.e. words).
 	anagram.Flags().UintSliceVarP(&op.unknown, "unknown", "u", []uint{}, "Number of unknown/open letters ([min,]max).\nUse a single number like '1' for an exact number of unknowns.\nOtherwise, use a range like '0,2'")
 
 	anagram.Flags().StringVarP(&op.filter, "filter", "f", "", "Pattern for filtering anagrams.")
-
-	anagram.MarkFlagsMutuallyExclusive("partial", "multi")
+	op.frequencyOptions.addFlags(anagram)
+	op.caseOptions.addFlags(anagram)
+	ignore.addFlags(anagram)
+	load.addFlags(anagram)
 
 	return anagram
 }
@@ -171,56 +218,16 @@ func interactiveFlags(answer string, op *anagramOptions, commands map[string]boo
 		fmt.Fprintln(&sb, "")
 		fmt.Fprintln(&sb, "To change the mode, enter #normal, #partial or #multi.")
 		fmt.Fprintln(&sb, "")
-		fmt.Fprintln(&sb, "To change flags, enter the flag's name and the value, separated by '='")
-		fmt.Fprintln(&sb, "Available flags with current setting:")
-		fmt.Fprintln(&sb, "")
-		fmt.Fprintf(&sb, "  filter = %s\n", op.filter)
-		if !op.multi {
-			fmt.Fprintf(&sb, "  unknown = %d,%d\n", op.minUnknown, op.maxUnknown)
-		} else {
-			fmt.Fprintf(&sb, "  unknown = %d,%d    (*)\n", op.minUnknown, op.maxUnknown)
-		}
-		if op.multi {
-			fmt.Fprintf(&sb, "  max-words = %d\n", op.maxWords)
-		} else {
-			fmt.Fprintf(&sb, "  max-words = %d    (*)\n", op.maxWords)
-		}
-		if op.multi || op.partial {
-			fmt.Fprintf(&sb, "  min-length = %d\n", op.minLength)
-		} else {
-			fmt.Fprintf(&sb, "  min-length = %d   (*)\n", op.minLength)
-		}
-
-		mode := "#normal"
-		if op.partial {
-			mode = "#partial"
-		} else if op.multi {
-			mode = "#multi"
-		}
-
-		fmt.Fprintf(&sb, "  (*)...ignored in mode %s\n", mode)
+		writeFlagsHelp(&sb, op, anagramMode(op))
 		fmt.Fprintln(&sb, "")
 		fmt.Fprintln(&sb, "To quit, enter nothing or press Ctrl+C")
 		return sb.String(), true
 	}
 	if strings.HasPrefix(answer, "#") {
-		switch answer {
-		case "#normal", "#n":
-			op.partial = false
-			op.multi = false
-			return "switched to mode #normal", true
-		case "#partial", "#p":
-			op.partial = true
-			op.multi = false
-			return "switched to mode #partial", true
-		case "#multi", "#m":
-			op.partial = false
-			op.multi = true
-			return "switched to mode #multi", true
-		default:
-			return fmt.Sprintf("failed to set mode: unknown mode #%s", answer), true
+		if !setMode(answer, op) {
+			return fmt.Sprintf("failed to set mode: unknown mode %s", answer), true
 		}
-
+		return fmt.Sprintf("switched to mode %s", anagramMode(op)), true
 	}
 	if strings.Contains(answer, "=") {
 		parts := strings.SplitN(answer, "=", 2)
@@ -235,7 +242,7 @@ func interactiveFlags(answer string, op *anagramOptions, commands map[string]boo
 		switch command {
 		case "filter", "f":
 			op.filter = value
-			pat, err := createPattern(op.filter)
+			pat, err := op.compile(op.filter)
 			if err != nil {
 				return fmt.Sprintf("failed to set filter: %s", err.Error()), true
 			}
@@ -262,10 +269,24 @@ func interactiveFlags(answer string, op *anagramOptions, commands map[string]boo
 		case "unknown", "u":
 			min, max, err := parseUnknownStr(value, op.multi)
 			if err != nil {
-				return fmt.Sprintf("failed to set min-length: %s", err.Error()), true
+				return fmt.Sprintf("failed to set unknown: %s", err.Error()), true
 			}
 			op.minUnknown, op.maxUnknown = min, max
 			return fmt.Sprintf("set unknown=%d,%d", op.minUnknown, op.maxUnknown), true
+		case "sort":
+			freq := frequencyOptions{sort: value, minFreq: op.minFreq}
+			if err := freq.validate(); err != nil {
+				return fmt.Sprintf("failed to set sort: %s", err.Error()), true
+			}
+			op.sort = value
+			return fmt.Sprintf("set sort=%s", op.sort), true
+		case "min-freq":
+			min, err := strconv.Atoi(value)
+			if err != nil || min < 0 {
+				return fmt.Sprintf("failed to set min-freq: invalid value '%s'", value), true
+			}
+			op.minFreq = min
+			return fmt.Sprintf("set min-freq=%d", op.minFreq), true
 		default:
 			return fmt.Sprintf("failed to set flags: unknown flag #%s", command), true
 		}
@@ -273,6 +294,51 @@ func interactiveFlags(answer string, op *anagramOptions, commands map[string]boo
 	return "", false
 }
 
+func setMode(mode string, op *anagramOptions) bool {
+	switch mode {
+	case "#normal", "#n":
+		op.partial = false
+		op.multi = false
+	case "#partial", "#p":
+		op.partial = true
+		op.multi = false
+	case "#multi", "#m":
+		op.partial = false
+		op.multi = true
+	default:
+		return false
+	}
+	return true
+}
+
+func anagramMode(op *anagramOptions) string {
+	if op.partial {
+		return "#partial"
+	} else if op.multi {
+		return "#multi"
+	}
+	return "#normal"
+}
+
+func writeFlagsHelp(sb *strings.Builder, op *anagramOptions, mode string) {
+	ignored := func(ign bool) string {
+		if ign {
+			return "    (*)"
+		}
+		return ""
+	}
+	fmt.Fprintln(sb, "To change flags, enter the flag's name and the value, separated by '='")
+	fmt.Fprintln(sb, "Available flags with current setting:")
+	fmt.Fprintln(sb, "")
+	fmt.Fprintf(sb, "  filter = %s%s\n", op.filter, ignored(mode == "#match"))
+	fmt.Fprintf(sb, "  unknown = %d,%d%s\n", op.minUnknown, op.maxUnknown, ignored(mode != "#normal" && mode != "#partial"))
+	fmt.Fprintf(sb, "  max-words = %d%s\n", op.maxWords, ignored(mode != "#multi"))
+	fmt.Fprintf(sb, "  min-length = %d%s\n", op.minLength, ignored(mode != "#partial" && mode != "#multi"))
+	fmt.Fprintf(sb, "  sort = %s\n", op.sort)
+	fmt.Fprintf(sb, "  min-freq = %d\n", op.minFreq)
+	fmt.Fprintf(sb, "  (*)...ignored in mode %s\n", mode)
+}
+
 func parseUnknownStr(unknownStr string, multi bool) (uint, uint, error) {
 	parts := strings.Split(unknownStr, ",")
 	unknown := make([]uint, len(parts), len(parts))
@@ -312,102 +378,137 @@ func parseUnknown(unknown []uint, multi bool) (uint, uint, error) {
 	return minUnknown, maxUnknown, nil
 }
 
-func printFiltered(leaf anagram.Leaf, pattern *regexp.Regexp) string {
-	temp := []string{}
-	for _, word := range leaf {
-		if pattern == nil || pattern.MatchString(word) {
-			temp = append(temp, word)
-		}
+func (op *anagramOptions) query() engine.Query {
+	return engine.Query{
+		MinLength:  op.minLength,
+		MaxWords:   op.maxWords,
+		MinUnknown: op.minUnknown,
+		MaxUnknown: op.maxUnknown,
+		Filter:     op.pattern,
+		MinFreq:    op.minFreq,
+		SortByFreq: op.sort == sortFreq,
 	}
-	if len(temp) == 0 {
-		return ""
+}
+
+func (f *frequencyOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.sort, "sort", "", "Sort order of results. Use 'freq' for the most frequent words first.\nDefaults to dictionary order.")
+	cmd.Flags().IntVar(&f.minFreq, "min-freq", 0, "Minimum word frequency. Excludes words without frequency.")
+}
+
+func (f *frequencyOptions) validate() error {
+	if f.sort != "" && f.sort != sortFreq {
+		return fmt.Errorf("unknown sort order '%s'. Use one of: %s", f.sort, sortFreq)
 	}
-	return strings.Join(temp, "  ")
+	if f.minFreq < 0 {
+		return fmt.Errorf("flag --min-freq must not be negative")
+	}
+	return nil
 }
 
-func printNormal(word string, tree *anagram.Tree, op anagramOptions) {
-	runes := util.UniqueRunes(word, true)
-	tempRunes := make(map[rune]int, len(runes))
-	ana := tree.AnagramsWithUnknown(word, op.minUnknown, op.maxUnknown)
-	for _, res := range ana {
-		line := printFiltered(res, op.pattern)
-		if line != "" {
-			fmt.Printf("  %s", line)
-			if op.maxUnknown > 0 {
-				for k := range tempRunes {
-					delete(tempRunes, k)
-				}
-				for k, v := range runes {
-					tempRunes[k] = v
-				}
-				add := util.FindAdditions(tempRunes, res[0], true)
-				if len(add) > 0 {
-					fmt.Printf("  (+%s)", string(add))
-				}
-			}
-			fmt.Print("\n")
-		}
+// query creates a query with the frequency options
+func (f *frequencyOptions) query() engine.Query {
+	return engine.Query{MinFreq: f.minFreq, SortByFreq: f.sort == sortFreq}
+}
+
+func (c *caseOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&c.caseSensitive, "case-sensitive", false, "Distinguish upper- and lower-case letters.")
+	cmd.Flags().BoolVar(&c.noProperNouns, "no-proper-nouns", false, "Exclude words starting with an upper-case letter.")
+	cmd.Flags().BoolVar(&c.onlyProperNouns, "only-proper-nouns", false, "Use only words starting with an upper-case letter.")
+}
+
+func (c *caseOptions) validate() error {
+	if c.noProperNouns && c.onlyProperNouns {
+		return fmt.Errorf("flags --no-proper-nouns and --only-proper-nouns are mutually exclusive")
 	}
+	return nil
 }
 
-func printPartial(word string, tree *anagram.Tree, op anagramOptions) {
-	runes := util.UniqueRunes(word, true)
-	tempRunes := make(map[rune]int, len(runes))
-	ana := tree.PartialAnagramsWithUnknown(word, op.minLength, op.minUnknown, op.maxUnknown)
-	for _, res := range ana {
-		line := printFiltered(res, op.pattern)
-		if line != "" {
-			fmt.Printf("  %s", line)
-			if op.maxUnknown > 0 {
-				for k := range tempRunes {
-					delete(tempRunes, k)
-				}
-				for k, v := range runes {
-					tempRunes[k] = v
-				}
-				add := util.FindAdditions(tempRunes, res[0], true)
-				if len(add) > 0 {
-					fmt.Printf("  (+%s)", string(add))
-				}
-			}
-			fmt.Print("\n")
-		}
+// engineOptions sets the case options in engine options
+func (c *caseOptions) engineOptions(opts engine.Options) engine.Options {
+	opts.CaseSensitive = c.caseSensitive
+	switch {
+	case c.noProperNouns:
+		opts.ProperNouns = engine.ProperNounsExclude
+	case c.onlyProperNouns:
+		opts.ProperNouns = engine.ProperNounsOnly
 	}
+	return opts
 }
 
-func printMulti(word string, tree *anagram.Tree, op anagramOptions) {
-	ana := tree.MultiAnagrams(word, op.maxWords, op.minLength, false)
-
-	for _, res := range ana {
-		found := op.pattern == nil
-		foundIndex := -1
-		if op.pattern != nil {
-		FindMatch:
-			for b, block := range res {
-				for _, word := range block {
-					if op.pattern.MatchString(word) {
-						found = true
-						foundIndex = b
-						break FindMatch
-					}
-				}
-			}
+// compile creates a pattern, case-sensitive if requested. See engine.Pattern
+func (c *caseOptions) compile(pattern string) (*regexp.Regexp, error) {
+	if c.caseSensitive {
+		return engine.CaseSensitivePattern(pattern)
+	}
+	return engine.Pattern(pattern)
+}
+
+func (i *ignoreOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&i.names, "ignore", []string{},
+		fmt.Sprintf("Characters to ignore in words and queries, comma-separated. Any of: %s.\nDefaults to the config, or to spaces and hyphens.", strings.Join(core.IgnoredNames, ", ")))
+}
+
+// characters resolves the ignored characters, from the flag or from the config
+func (i *ignoreOptions) characters(config *core.Config) (string, error) {
+	return config.Ignored(i.names)
+}
+
+func progressBar(out io.Writer) func(int) {
+	return func(percent int) {
+		bar := strings.Repeat("#", percent/2)
+		fmt.Fprintf(out, "\rBuilding tree: [%-50s]", bar)
+		if percent >= 100 {
+			fmt.Fprintln(out)
 		}
-		if !found {
-			continue
+	}
+}
+
+// loadWords loads and merges the given dictionaries and groups, or the current dictionary if none are given.
+// The load filter is applied in addition to the filters of the dictionaries.
+func loadWords(config *core.Config, names []string, filter util.LoadFilter) ([]string, util.Sources, util.Frequencies, error) {
+	dictionaries, err := config.GetDicts(names)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for i := range dictionaries {
+		dictionaries[i].Filter = dictionaries[i].Filter.Merge(filter)
+	}
+	return util.LoadDictionaries(config.Root, dictionaries)
+}
+
+// formatWords joins words for printing, with their source dictionaries if sources is not nil
+func formatWords(words []string, sources util.Sources) string {
+	if sources == nil {
+		return strings.Join(words, "  ")
+	}
+	labeled := make([]string, len(words))
+	for i, word := range words {
+		labeled[i] = fmt.Sprintf("%s [%s]", word, sources.Label(word))
+	}
+	return strings.Join(labeled, "  ")
+}
+
+func printAnagrams(out io.Writer, anagrams []engine.Anagram, sources util.Sources) int {
+	for _, ana := range anagrams {
+		fmt.Fprintf(out, "  %s", formatWords(ana.Words, sources))
+		if len(ana.Added) > 0 {
+			fmt.Fprintf(out, "  (+%s)", string(ana.Added))
 		}
+		fmt.Fprint(out, "\n")
+	}
+	return len(anagrams)
+}
 
-		fmt.Print("  ")
-		for b, block := range res {
-			if b == foundIndex {
-				fmt.Print(printFiltered(block, op.pattern))
-			} else {
-				fmt.Print(strings.Join(block, "  "))
-			}
-			if b < len(res)-1 {
-				fmt.Print("  |  ")
+func printMulti(out io.Writer, anagrams []engine.MultiAnagram, sources util.Sources) int {
+	for _, ana := range anagrams {
+		fmt.Fprint(out, "  ")
+		for b, block := range ana.Words {
+			fmt.Fprint(out, formatWords(block, sources))
+			if b < len(ana.Words)-1 {
+				fmt.Fprint(out, "  |  ")
 			}
 		}
-		fmt.Println()
+		fmt.Fprintln(out)
 	}
+	return len(anagrams)
 }
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/mlange-42/xwrd/core"
+	"github.com/mlange-42/xwrd/util"
+	"github.com/spf13/cobra"
+)
+
+func buildDictCommand(config *core.Config) *cobra.Command {
+	opts := util.CorpusOptions{}
+
+	build := &cobra.Command{
+		Use:   "build CORPUS... DICT",
+		Short: "Build dictionaries from text corpora",
+		Long: `Build dictionaries from text corpora.
+
+Counts the words in text files, and installs them as a new dictionary,
+with the number of occurrences in a tab-separated second column.
+Words are sorted by frequency, most frequent first.
+
+Arguments can be text files, or directories that are searched recursively.
+Compressed files and legacy encodings are handled like for dict install.
+
+Words are sequences of letters, and can contain single apostrophes or hyphens.
+Spellings that only differ in case are merged, using the most frequent spelling, unless --keep-case is given.
+
+Examples
+--------
+
+xwrd dict build books/ en/books
+xwrd dict build --min-count 3 --min-length 2 news.txt articles.txt en/news
+`,
+		Args: util.WrappedArgs(cobra.MinimumNArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			dictionary := util.NewDict(args[len(args)-1])
+			if util.HasDictionary(config.Root, dictionary) {
+				return fmt.Errorf("failed to build dictionary: %w: %s", util.ErrDictionaryExists, dictionary.FullName())
+			}
+
+			files, err := corpusFiles(args[:len(args)-1])
+			if err != nil {
+				return fmt.Errorf("failed to build dictionary: %w", err)
+			}
+
+			corpus := util.NewCorpus()
+			for _, file := range files {
+				content, err := util.ReadWordList(config.Root, file)
+				if err == nil {
+					err = util.CheckText(content)
+				}
+				if err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "skipping %s: %s\n", file, err.Error())
+					continue
+				}
+				corpus.Add(string(content))
+			}
+
+			words := corpus.Words(opts)
+			lines := make([]string, len(words))
+			for i, w := range words {
+				lines[i] = w.String()
+			}
+			dictionary.Description = fmt.Sprintf("Built from %d files in %s", len(files), strings.Join(args[:len(args)-1], ", "))
+			count, err := util.SaveDictionary(config.Root, dictionary, lines)
+			if err != nil {
+				return fmt.Errorf("failed to build dictionary: %w", err)
+			}
+			fmt.Fprintf(out, "built dictionary %s with %d words from %d files\n", dictionary.FullName(), count, len(files))
+			return nil
+		},
+	}
+	build.Flags().IntVar(&opts.MinCount, "min-count", 1, "Minimum number of occurrences of words.")
+	build.Flags().IntVar(&opts.MinLength, "min-length", 1, "Minimum length of words.")
+	build.Flags().BoolVar(&opts.KeepCase, "keep-case", false, "Count spellings that differ in case separately.")
+
+	return build
+}
+
+// corpusFiles lists the given files, and all files in the given directories, recursively
+func corpusFiles(paths []string) ([]string, error) {
+	files := []string{}
+	for _, path := range paths {
+		if !util.DirExists(path) {
+			if !util.FileExists(path) {
+				return nil, fmt.Errorf("file not found: %s", path)
+			}
+			files = append(files, path)
+			continue
+		}
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.Type().IsRegular() {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mlange-42/xwrd/core"
+	"github.com/mlange-42/xwrd/util"
+	"github.com/spf13/cobra"
+)
+
+func checkDictCommand(config *core.Config) *cobra.Command {
+	var maxIssues int
+
+	check := &cobra.Command{
+		Use:   "check [DICT|FILE]",
+		Short: "Check dictionaries for problems",
+		Long: `Check dictionaries for problems.
+
+Reports carriage returns (CRLF line endings), leading or trailing whitespace,
+empty lines, comment lines starting with '#' and duplicate words, with line numbers.
+Fix them with command dict normalize.
+
+Checks installed dictionaries or plain word list files.
+Use without arguments to check the current dictionary.
+Exits with an error if problems were found.
+`,
+		Aliases: []string{"c"},
+		Args:    util.WrappedArgs(cobra.MaximumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			source := ""
+			if len(args) > 0 {
+				source = args[0]
+			}
+			if source == "" || strings.HasPrefix(source, core.GroupPrefix) {
+				dictionary, err := singleDict(cmd, config, source)
+				if err != nil {
+					return fmt.Errorf("failed to check dictionary: %w", err)
+				}
+				source = dictionary.FullName()
+			}
+
+			content, err := util.ReadWordList(config.Root, source)
+			if err != nil {
+				return fmt.Errorf("failed to check dictionary: %w", err)
+			}
+			issues := util.CheckWords(content)
+
+			counts := map[string]int{}
+			for i, issue := range issues {
+				counts[issue.Kind]++
+				if maxIssues >= 0 && i >= maxIssues {
+					continue
+				}
+				fmt.Fprintf(out, "  line %6d: %-30s %q\n", issue.Line, issue.Kind, issue.Text)
+			}
+			if maxIssues >= 0 && len(issues) > maxIssues {
+				fmt.Fprintf(out, "  ... and %d more\n", len(issues)-maxIssues)
+			}
+
+			for _, kind := range []string{util.IssueCarriageReturn, util.IssueWhitespace, util.IssueEmpty, util.IssueComment, util.IssueDuplicate} {
+				fmt.Fprintf(out, "%-31s %8d\n", kind+":", counts[kind])
+			}
+
+			if len(issues) > 0 {
+				return fmt.Errorf("found %d problems in %s. Fix them with: xwrd dict normalize", len(issues), source)
+			}
+			return nil
+		},
+	}
+	check.Flags().IntVarP(&maxIssues, "max", "n", 20, "Maximum number of problems to list. Lists all if negative.")
+
+	return check
+}
+
+func normalizeDictCommand(config *core.Config) *cobra.Command {
+	normalize := &cobra.Command{
+		Use:   "normalize [DICT]",
+		Short: "Fix problems in installed dictionaries",
+		Long: `Fix problems in installed dictionaries.
+
+Strips whitespace and carriage returns, and removes empty lines, comment lines and duplicates.
+See command dict check for the problems that are fixed.
+Dictionaries are normalized automatically when installed.
+
+Use without arguments to normalize the current dictionary.
+`,
+		Aliases: []string{"n"},
+		Args:    util.WrappedArgs(cobra.MaximumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+			dictionary, err := singleDict(cmd, config, name)
+			if err != nil {
+				return fmt.Errorf("failed to normalize dictionary: %w", err)
+			}
+
+			fixed, err := util.NormalizeDictionary(config.Root, dictionary)
+			if err != nil {
+				return fmt.Errorf("failed to normalize dictionary: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "normalized dictionary %s, fixed %d problems\n", dictionary.FullName(), fixed)
+			return nil
+		},
+	}
+	return normalize
+}
@@ -0,0 +1,506 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/xwrd/core"
+	"github.com/mlange-42/xwrd/util"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+type cliTest struct {
+	title string
+	args  []string
+	input string
+}
+
+// cliSession is a sequence of steps run in the same storage directory, with the combined output compared to a golden file
+type cliSession struct {
+	title string
+	// config is the initial config. Uses dictionary en/test if no dictionary is given
+	config core.Config
+	steps  []cliStep
+}
+
+// cliStep is a step of a cliSession. Runs a command, writes a file, or shows a file
+type cliStep struct {
+	// args are the command line arguments. $ROOT is replaced by the storage directory
+	args  []string
+	input string
+	// write is the path of a file to write, relative to the storage directory.
+	// The content is taken from file from, if given
+	write   string
+	content string
+	from    string
+	// show is the path of a file to show, relative to the storage directory
+	show string
+}
+
+// timestamps matches dates and times, which are masked in the output of sessions
+var timestamps = regexp.MustCompile(`\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}:\d{2}Z)?`)
+
+// setupRootDir creates a temporary storage directory with the test dictionaries
+func setupRootDir(t *testing.T) string {
+	dir := t.TempDir()
+
+	src := filepath.Join("testdata", "dict")
+	languages, err := os.ReadDir(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, lang := range languages {
+		files, err := os.ReadDir(filepath.Join(src, lang.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = os.MkdirAll(filepath.Join(dir, "dict", lang.Name()), 0755); err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range files {
+			content, err := os.ReadFile(filepath.Join(src, lang.Name(), file.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = os.WriteFile(filepath.Join(dir, "dict", lang.Name(), file.Name()), content, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return dir
+}
+
+// runCommand runs the CLI in-process and returns its output, including the exit code and error message
+func runCommand(dir string, config *core.Config, args []string, input string) string {
+	out := bytes.Buffer{}
+	errOut := bytes.Buffer{}
+	root := RootCommand(config, "test", Streams{In: strings.NewReader(input), Out: &out, Err: &errOut}, dir)
+	root.SetArgs(args)
+
+	err := root.Execute()
+
+	fmt.Fprintf(&out, "\n-- exit code: %d\n", ExitCode(err))
+	if err != nil {
+		fmt.Fprintf(&out, "-- error: %s\n", err.Error())
+	}
+	return out.String()
+}
+
+func checkGolden(t *testing.T, title string, actual string) {
+	path := filepath.Join("testdata", "golden", strings.ReplaceAll(title, " ", "-")+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(actual), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(expected), actual, "Wrong output in %s", title)
+}
+
+func runGolden(t *testing.T, tests []cliTest) {
+	for _, test := range tests {
+		dir := setupRootDir(t)
+		config := core.Config{Dict: "en/test"}
+		actual := runCommand(dir, &config, test.args, test.input)
+		checkGolden(t, test.title, actual)
+	}
+}
+
+func runSessions(t *testing.T, sessions []cliSession) {
+	for _, session := range sessions {
+		dir := setupRootDir(t)
+		config := session.config
+		if config.Dict == "" {
+			config.Dict = "en/test"
+		}
+
+		out := strings.Builder{}
+		for _, step := range session.steps {
+			switch {
+			case step.write != "":
+				content := []byte(step.content)
+				if step.from != "" {
+					var err error
+					if content, err = os.ReadFile(step.from); err != nil {
+						t.Fatal(err)
+					}
+				}
+				path := filepath.Join(dir, step.write)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, content, 0644); err != nil {
+					t.Fatal(err)
+				}
+				fmt.Fprintf(&out, "$ write %s\n\n", step.write)
+			case step.show != "":
+				content, err := os.ReadFile(filepath.Join(dir, step.show))
+				if os.IsNotExist(err) {
+					fmt.Fprintf(&out, "$ show %s\n-- not found\n\n", step.show)
+					continue
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				fmt.Fprintf(&out, "$ show %s\n%s\n", step.show, content)
+			default:
+				args := make([]string, len(step.args))
+				for i, arg := range step.args {
+					args[i] = strings.ReplaceAll(arg, "$ROOT", dir)
+				}
+				fmt.Fprintf(&out, "$ xwrd %s\n", strings.Join(step.args, " "))
+				out.WriteString(runCommand(dir, &config, args, step.input))
+				out.WriteString("\n")
+			}
+		}
+		actual := strings.ReplaceAll(out.String(), dir, "$ROOT")
+		checkGolden(t, session.title, timestamps.ReplaceAllString(actual, "<date>"))
+	}
+}
+
+func TestAnagramCommand(t *testing.T) {
+	runGolden(t, []cliTest{
+		{title: "anagram", args: []string{"anagram", "listen", "stone"}},
+		{title: "anagram no results", args: []string{"anagram", "xyz"}},
+		{title: "anagram unknown", args: []string{"anagram", "--unknown", "1", "ton"}},
+		{title: "anagram filter", args: []string{"anagram", "--filter", "s*", "listen"}},
+		{title: "anagram partial", args: []string{"anagram", "--partial", "--min-length", "4", "listen"}},
+		{title: "anagram multi", args: []string{"anagram", "--multi", "--max-words", "2", "listen"}},
+		{title: "anagram other dict", args: []string{"anagram", "--dict", "de/test", "lampe"}},
+		{title: "anagram missing dict", args: []string{"anagram", "--dict", "de/missing", "lampe"}},
+		{title: "anagram starter dict", args: []string{"anagram", "--dict", "en/starter", "listen", "heart"}},
+		{title: "anagram bad flags", args: []string{"anagram", "--max-words", "2", "listen"}},
+		{title: "anagram exclusive flags", args: []string{"anagram", "--partial", "--multi", "listen"}},
+		{title: "anagram bad filter", args: []string{"anagram", "--filter", "[", "listen"}},
+		{
+			title: "anagram interactive",
+			args:  []string{"anagram"},
+			input: "listen\n?\n#partial\nl=5\nlisten\n#x\n\n",
+		},
+	})
+}
+
+func TestMatchCommand(t *testing.T) {
+	runGolden(t, []cliTest{
+		{title: "match", args: []string{"match", "l...", "*one"}},
+		{title: "match no results", args: []string{"match", "x*"}},
+		{title: "match bad pattern", args: []string{"match", "["}},
+		{title: "match missing dict", args: []string{"match", "--dict", "de/missing", "l..."}},
+		{
+			title: "match interactive",
+			args:  []string{"match"},
+			input: "s..\n[\n*ple\n\n",
+		},
+	})
+}
+
+func TestPhraseCommand(t *testing.T) {
+	runGolden(t, []cliTest{
+		{
+			title: "phrase",
+			args:  []string{"phrase", "--min-length", "3", "listen", "tone"},
+			input: "enlist\nxyz\n#undo\n#undo\n#redo\n#redo\nnote\n",
+		},
+		{
+			title: "phrase quit",
+			args:  []string{"phrase", "apple"},
+			input: "\n",
+		},
+		{title: "phrase no args", args: []string{"phrase"}},
+	})
+}
+
+func TestShellCommand(t *testing.T) {
+	runGolden(t, []cliTest{
+		{
+			title: "shell",
+			args:  []string{"shell"},
+			input: "listen\n#partial\nl=5\nlisten\n#match\nl...\n[\n#dict de/test\n#normal\nlampe\n?\n#dict de/missing\n#dict\n#x\n\n",
+		},
+		{title: "shell missing dict", args: []string{"shell", "--dict", "de/missing"}},
+	})
+}
+
+func TestDictCommand(t *testing.T) {
+	runGolden(t, []cliTest{
+		{title: "dict info", args: []string{"dict", "info"}},
+		{title: "dict list", args: []string{"dict", "list"}},
+		{title: "dict set", args: []string{"dict", "set", "de/test"}},
+		{title: "dict set missing", args: []string{"dict", "set", "de/missing"}},
+		{title: "dict install unknown", args: []string{"dict", "install", "xx/missing"}},
+		{title: "dict install existing", args: []string{"dict", "install", "en/test"}},
+		{title: "dict install from file", args: []string{"dict", "install", "--from", "testdata/sources/words.txt", "fr/words"}},
+		{title: "dict install from binary", args: []string{"dict", "install", "--from", "testdata/sources/binary.dat", "fr/words"}},
+		{title: "dict install from missing", args: []string{"dict", "install", "--from", "testdata/sources/missing.txt", "fr/words"}},
+		{title: "dict analyze", args: []string{"dict", "analyze", "de/test"}},
+		{title: "dict remove", args: []string{"dict", "remove", "de/test"}},
+		{title: "dict remove current", args: []string{"dict", "remove", "en/test"}},
+		{title: "dict remove missing", args: []string{"dict", "remove", "de/missing"}},
+		{title: "dict remove starter", args: []string{"dict", "remove", "de/starter"}},
+		{title: "dict rename", args: []string{"dict", "rename", "de/test", "de/renamed"}},
+		{title: "dict rename existing", args: []string{"dict", "rename", "de/test", "en/test"}},
+		{title: "dict update missing", args: []string{"dict", "update", "de/missing"}},
+		{title: "dict import-hunspell", args: []string{"dict", "import-hunspell", "testdata/sources/hunspell.dic", "testdata/sources/hunspell.aff", "de/hunspell"}},
+		{title: "dict import-hunspell missing", args: []string{"dict", "import-hunspell", "testdata/sources/missing.dic", "testdata/sources/hunspell.aff", "de/hunspell"}},
+		{title: "dict build", args: []string{"dict", "build", "--min-count", "2", "testdata/corpus", "en/corpus"}},
+		{title: "dict build missing", args: []string{"dict", "build", "testdata/corpus/missing.txt", "en/corpus"}},
+		{title: "dict check", args: []string{"dict", "check"}},
+		{title: "dict check file", args: []string{"dict", "check", "testdata/sources/messy.txt"}},
+		{title: "dict check max", args: []string{"dict", "check", "testdata/sources/messy.txt", "--max", "2"}},
+		{title: "dict install from latin1", args: []string{"dict", "install", "--from", "testdata/sources/latin1.txt", "de/latin"}},
+		{title: "dict install bad encoding", args: []string{"dict", "install", "--from", "testdata/sources/latin1.txt", "--encoding", "ebcdic", "de/latin"}},
+		{title: "dict install from messy", args: []string{"dict", "install", "--from", "testdata/sources/messy.txt", "en/messy"}},
+		{title: "dict intersect", args: []string{"dict", "intersect", "en/test", "testdata/sources/subset.txt", "--print"}},
+		{title: "dict diff", args: []string{"dict", "diff", "testdata/sources/subset.txt", "en/test", "--print", "--out", "en/diff"}},
+		{title: "dict union missing", args: []string{"dict", "union", "en/test", "en/missing"}},
+		{title: "dict diff existing", args: []string{"dict", "diff", "en/test", "de/test", "--out", "de/test"}},
+		{title: "dict overlay", args: []string{"dict", "overlay"}},
+		{title: "dict overlay missing", args: []string{"dict", "overlay", "de/missing"}},
+		{title: "dict add-word missing", args: []string{"dict", "add-word", "--dict", "de/missing", "foo"}},
+		{title: "dict bad args", args: []string{"dict", "set"}},
+	})
+}
+
+func TestSessions(t *testing.T) {
+	runSessions(t, []cliSession{
+		{
+			title: "session dict set",
+			steps: []cliStep{
+				{args: []string{"dict", "set", "de/test"}},
+				{show: "config.yml"},
+			},
+		},
+		{
+			title: "session dict update",
+			steps: []cliStep{
+				{write: "words.txt", content: "alpha\nbeta\n"},
+				{args: []string{"dict", "install", "--from", "$ROOT/words.txt", "fr/words"}},
+				{args: []string{"dict", "update", "--from", "$ROOT/words.txt", "fr/words"}},
+				{write: "words.txt", content: "alpha\nbeta\ngamma\n"},
+				{args: []string{"dict", "update", "--from", "$ROOT/words.txt", "fr/words"}},
+				{args: []string{"match", "--dict", "fr/words", "g*"}},
+				{args: []string{"dict", "block-word", "--dict", "fr/words", "gamma"}},
+				{args: []string{"dict", "update", "--from", "$ROOT/words.txt", "fr/words"}},
+				{args: []string{"match", "--dict", "fr/words", "*a"}},
+			},
+		},
+		{
+			title: "session dict rename current",
+			steps: []cliStep{
+				{args: []string{"dict", "rename", "en/test", "en/renamed"}},
+				{show: "config.yml"},
+			},
+		},
+		{
+			title: "session dict overlay",
+			steps: []cliStep{
+				{args: []string{"dict", "add-word", "elints", "enlists"}},
+				{args: []string{"dict", "block-word", "silent", "enlists"}},
+				{args: []string{"dict", "overlay"}},
+				{args: []string{"anagram", "listen"}},
+				{args: []string{"dict", "add-word", "--undo", "silent"}},
+				{args: []string{"dict", "overlay"}},
+				{args: []string{"dict", "block-word", "--undo", "silent", "listen"}},
+				{args: []string{"anagram", "listen"}},
+			},
+		},
+		{
+			title: "session combined dicts",
+			steps: []cliStep{
+				{write: "dict/en/names.lst", content: "Elints\nsilent\nTom\n"},
+				{args: []string{"anagram", "--dict", "en/test,en/names", "--sources", "listen"}},
+				{args: []string{"match", "-d", "en/names", "-d", "en/test", "t.."}},
+				{args: []string{"dict", "group", "english", "en/test", "names"}},
+				{args: []string{"dict", "set", "@english"}},
+				{args: []string{"match", "T*"}},
+				{args: []string{"dict", "remove", "en/names"}},
+				{args: []string{"dict", "rename", "en/names", "en/people"}},
+				{show: "config.yml"},
+				{args: []string{"match", "--dict", "@missing", "t.."}},
+			},
+		},
+		{
+			title:  "session current group",
+			config: core.Config{Dict: "@g", Groups: map[string][]string{"g": {"en/test", "de/test"}}},
+			steps: []cliStep{
+				{args: []string{"dict", "analyze"}},
+				{args: []string{"dict", "add-word", "foo"}},
+				{args: []string{"dict", "block-word", "foo"}},
+				{args: []string{"dict", "overlay"}},
+				{args: []string{"dict", "check"}},
+				{args: []string{"dict", "normalize"}},
+				{args: []string{"dict", "overlay", "@g"}},
+				{args: []string{"dict", "add-word", "--dict", "en/test", "foo"}},
+			},
+		},
+		{
+			title: "session dict normalize",
+			steps: []cliStep{
+				{write: "dict/en/messy.lst", from: "testdata/sources/messy.txt"},
+				{args: []string{"dict", "check", "en/messy"}},
+				{args: []string{"match", "--dict", "en/messy", "*a"}},
+				{args: []string{"dict", "normalize", "en/messy"}},
+				{args: []string{"dict", "check", "en/messy"}},
+				{show: "dict/en/messy.lst"},
+			},
+		},
+		{
+			title: "session legacy encoding",
+			steps: []cliStep{
+				{write: "dict/de/latin.lst", from: "testdata/sources/latin1.txt"},
+				{args: []string{"match", "--dict", "de/latin", "*e"}},
+				{args: []string{"anagram", "--dict", "de/latin", "wöme"}},
+				{args: []string{"dict", "install", "--from", "testdata/sources/latin1.txt", "--encoding", "latin-1", "de/installed"}},
+				{show: "dict/de/installed.lst"},
+			},
+		},
+		{
+			title: "session dict import-hunspell",
+			steps: []cliStep{
+				{args: []string{"dict", "import-hunspell", "testdata/sources/hunspell.dic", "testdata/sources/hunspell.aff", "de/hunspell"}},
+				{args: []string{"match", "--dict", "de/hunspell", "*n"}},
+			},
+		},
+		{
+			title: "session dict build",
+			steps: []cliStep{
+				{args: []string{"dict", "build", "testdata/corpus/fox.txt", "testdata/corpus/more", "en/corpus"}},
+				{show: "dict/en/corpus.lst"},
+				{args: []string{"match", "--dict", "en/corpus", "*n"}},
+				{args: []string{"anagram", "--dict", "en/corpus", "gdo"}},
+				{args: []string{"dict", "union", "en/corpus", "en/test", "--out", "en/union"}},
+				{show: "dict/en/union.lst"},
+			},
+		},
+		{
+			title: "session frequencies",
+			steps: []cliStep{
+				{args: []string{"dict", "build", "testdata/corpus", "en/corpus"}},
+				{args: []string{"match", "--dict", "en/corpus", "--sort", "freq", "--min-freq", "2", "*o*"}},
+				{args: []string{"anagram", "--dict", "en/corpus", "--partial", "--sort", "freq", "--min-freq", "2", "thedogs"}},
+				{args: []string{"match", "--dict", "en/corpus", "--sort", "rank", "*o*"}},
+			},
+		},
+		{
+			title: "session dict metadata",
+			steps: []cliStep{
+				{write: "woerter.txt", content: "gr\xfcn\nBlau\n"},
+				{args: []string{"dict", "install", "--from", "$ROOT/woerter.txt", "de/words"}},
+				{args: []string{"dict", "info", "de/words"}},
+				{write: "woerter.txt", content: "gr\xfcn\nBlau\nrot\n"},
+				{args: []string{"dict", "update", "de/words"}},
+				{args: []string{"dict", "rename", "de/words", "de/colors"}},
+				{args: []string{"dict", "info", "de/colors"}},
+				{args: []string{"dict", "remove", "de/colors"}},
+				{show: "dict/de/colors.meta.yml"},
+			},
+		},
+		{
+			title: "session load filters",
+			steps: []cliStep{
+				{args: []string{"match", "--lengths", "5-", "--exclude", "^s", "*n*"}},
+				{args: []string{"match", "--lengths", "x", "*"}},
+				{args: []string{"dict", "filter", "en/test", "--lengths", "6-6"}},
+				{show: "config.yml"},
+				{args: []string{"anagram", "--partial", "listen"}},
+				{args: []string{"dict", "info"}},
+				{args: []string{"dict", "filter", "en/test", "--remove"}},
+				{args: []string{"dict", "filter", "en/test"}},
+			},
+		},
+		{
+			title: "session case sensitive",
+			steps: []cliStep{
+				{args: []string{"dict", "install", "--from", "testdata/sources/names.txt", "en/names"}},
+				{args: []string{"anagram", "--dict", "en/names", "hsilop", "amy"}},
+				{args: []string{"anagram", "--dict", "en/names", "--case-sensitive", "hsilop", "aMy"}},
+				{args: []string{"anagram", "--dict", "en/names", "--no-proper-nouns", "amy"}},
+				{args: []string{"match", "--dict", "en/names", "--only-proper-nouns", "*"}},
+				{args: []string{"match", "--dict", "en/names", "--case-sensitive", "*a*"}},
+				{args: []string{"match", "--dict", "en/names", "--case-sensitive", "M*"}},
+				{args: []string{"match", "--no-proper-nouns", "--only-proper-nouns", "*"}},
+			},
+		},
+		{
+			title: "session ignored characters",
+			steps: []cliStep{
+				{args: []string{"dict", "install", "--from", "testdata/sources/phrases.txt", "en/phrases"}},
+				{args: []string{"anagram", "--dict", "en/phrases", "ceramic e", "woman-hitler", "clocko"}},
+				{args: []string{"anagram", "--dict", "en/phrases", "--ignore", "apostrophes", "clocko"}},
+			},
+		},
+		{
+			title:  "session ignored characters config",
+			config: core.Config{Dict: "en/test", Ignore: []string{"spaces", "hyphens", "apostrophes"}},
+			steps: []cliStep{
+				{args: []string{"dict", "install", "--from", "testdata/sources/phrases.txt", "en/phrases"}},
+				{args: []string{"anagram", "--dict", "en/phrases", "--multi", "clocko cinema"}},
+				{args: []string{"anagram", "--ignore", "commas", "listen"}},
+			},
+		},
+	})
+}
+
+func TestDictInstallFrom(t *testing.T) {
+	dir := setupRootDir(t)
+	config := core.Config{Dict: "en/test"}
+
+	source, err := filepath.Abs(filepath.Join("testdata", "sources", "words.txt"))
+	assert.Nil(t, err)
+
+	out := runCommand(dir, &config, []string{"dict", "install", "--from", "file://" + filepath.ToSlash(source), "fr/words"}, "")
+	assert.Contains(t, out, "installed dictionary fr/words with 3 words")
+	assert.Contains(t, out, "-- exit code: 0")
+
+	out = runCommand(dir, &config, []string{"dict", "list"}, "")
+	assert.Contains(t, out, "  fr/words                3 words, installed ")
+
+	out = runCommand(dir, &config, []string{"match", "--dict", "fr/words", "*a"}, "")
+	assert.Contains(t, out, "  alpha\n  beta\n  gamma\n")
+}
+
+func TestRootDirConcurrent(t *testing.T) {
+	for _, dict := range []string{"en/test", "de/test"} {
+		dict := dict
+		t.Run(dict, func(t *testing.T) {
+			t.Parallel()
+			dir := setupRootDir(t)
+			config := core.Config{Dict: "en/test"}
+			out := runCommand(dir, &config, []string{"dict", "set", dict}, "")
+			assert.Contains(t, out, "-- exit code: 0")
+
+			saved, err := core.LoadConfig(dir)
+			assert.Nil(t, err)
+			assert.Equal(t, dict, saved.Dict, "Config not saved in command's root dir")
+		})
+	}
+}
+
+func TestStarterNotInstalled(t *testing.T) {
+	dir := setupRootDir(t)
+	config := core.Config{Dict: "en/starter"}
+
+	out := runCommand(dir, &config, []string{"--help"}, "")
+	assert.Contains(t, out, "-- exit code: 0")
+	out = runCommand(dir, &config, []string{"anagram", "listen"}, "")
+	assert.Contains(t, out, "  enlist  inlets  listen  silent  tinsel\n")
+	out = runCommand(dir, &config, []string{"dict", "info"}, "")
+	assert.Contains(t, out, "source:      embedded\n")
+
+	for _, dict := range util.StarterDictionaries() {
+		_, installed := util.InstalledDictPath(dir, dict)
+		assert.False(t, installed, "Starter dictionary %s should not be installed", dict.FullName())
+		assert.False(t, util.FileExists(util.MetadataPath(dir, dict)), "Metadata of %s should not be written", dict.FullName())
+	}
+}
@@ -1,15 +1,17 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"io"
 	"sort"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/mlange-42/xwrd/anagram"
 	"github.com/mlange-42/xwrd/core"
+	"github.com/mlange-42/xwrd/engine"
 	"github.com/mlange-42/xwrd/util"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/maps"
@@ -20,28 +22,78 @@ func dictCommand(config *core.Config) *cobra.Command {
 		Use:     "dict",
 		Short:   "Handle dictionaries",
 		Aliases: []string{"d"},
-		Run: func(cmd *cobra.Command, args []string) {
-			_ = cmd.Help()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
 		},
 	}
 
 	root.AddCommand(showDictsCommand(config))
 	root.AddCommand(setDictCommand(config))
+	root.AddCommand(groupDictCommand(config))
+	root.AddCommand(filterDictCommand(config))
 	root.AddCommand(listDictsCommand(config))
 	root.AddCommand(installDictCommand(config))
+	root.AddCommand(updateDictCommand(config))
+	root.AddCommand(removeDictCommand(config))
+	root.AddCommand(renameDictCommand(config))
+	root.AddCommand(addWordCommand(config))
+	root.AddCommand(blockWordCommand(config))
+	root.AddCommand(overlayCommand(config))
+	root.AddCommand(importHunspellCommand(config))
+	root.AddCommand(buildDictCommand(config))
+	root.AddCommand(checkDictCommand(config))
+	root.AddCommand(normalizeDictCommand(config))
+	root.AddCommand(diffDictCommand(config))
+	root.AddCommand(unionDictCommand(config))
+	root.AddCommand(intersectDictCommand(config))
 	root.AddCommand(analyzeDictCommand(config))
 
 	return root
 }
 
+// singleDict resolves the name of a single dictionary, or the current dictionary if the name is empty.
+// Returns a usage error for groups.
+func singleDict(cmd *cobra.Command, config *core.Config, name string) (util.Dict, error) {
+	dict, err := config.GetDict(name)
+	if errors.Is(err, core.ErrGroup) {
+		return dict, util.UsageError(cmd, err)
+	}
+	return dict, err
+}
+
 func showDictsCommand(config *core.Config) *cobra.Command {
 	download := &cobra.Command{
-		Use:     "info",
-		Short:   "Shows the currently set dictionary",
+		Use:   "info [DICT]",
+		Short: "Shows the currently set dictionary",
+		Long: `Shows the currently set dictionary, or the given dictionary or group.
+
+Shows the dictionary's metadata, like source, license, installation date and word count.
+`,
 		Aliases: []string{"i"},
-		Args:    util.WrappedArgs(cobra.NoArgs),
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Printf(config.Dict)
+		Args:    util.WrappedArgs(cobra.MaximumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			name := config.Dict
+			if len(args) > 0 {
+				name = args[0]
+			}
+			dictionaries, err := config.GetDicts(args)
+			if err != nil {
+				return fmt.Errorf("failed to show dictionary: %w", err)
+			}
+
+			if !strings.HasPrefix(name, core.GroupPrefix) {
+				fmt.Fprintln(out, dictionaries[0].FullName())
+				return printMetadata(out, config, dictionaries[0], "  ")
+			}
+			fmt.Fprintln(out, name)
+			for _, dictionary := range dictionaries {
+				fmt.Fprintf(out, "  %s\n", dictionary.FullName())
+				if err := printMetadata(out, config, dictionary, "    "); err != nil {
+					return err
+				}
+			}
+			return nil
 		},
 	}
 	return download
@@ -53,104 +105,428 @@ func listDictsCommand(config *core.Config) *cobra.Command {
 		Short:   "List installable and installed dictionaries",
 		Aliases: []string{"l"},
 		Args:    util.WrappedArgs(cobra.NoArgs),
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("Available:")
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
 
-			for lang, dicts := range util.Dictionaries {
-				for _, d := range dicts {
-					fmt.Printf("  %s/%s\n", lang, d.Name)
-				}
+			registry, err := util.LoadRegistry(config.Root)
+			if err != nil {
+				return fmt.Errorf("failed to list dictionaries: %w", err)
 			}
 
-			allDicts, err := util.AllDictionaries()
+			fmt.Fprintln(out, "Available:")
+			for _, d := range registry.Dictionaries {
+				fmt.Fprintf(out, "  %-16s %s\n", d.FullName(), d.Description)
+			}
+
+			allDicts, err := util.AllDictionaries(config.Root)
 			if err != nil {
-				fmt.Printf("failed to list dictionaries: %s", err.Error())
-				return
+				return fmt.Errorf("failed to list dictionaries: %w", err)
 			}
 
 			keys := maps.Keys(allDicts)
 			sort.Strings(keys)
-			fmt.Println("Installed:")
+			fmt.Fprintln(out, "Installed:")
 			for _, key := range keys {
 				dict := allDicts[key]
-				fmt.Printf("  %s/%s\n", dict.Language, strings.TrimSuffix(dict.Name, filepath.Ext(dict.Name)))
+				meta, ok, err := util.LoadMetadata(config.Root, dict)
+				if err != nil {
+					return fmt.Errorf("failed to list dictionaries: %w", err)
+				}
+				if !ok {
+					fmt.Fprintf(out, "  %s\n", dict.FullName())
+					continue
+				}
+				installed := "installed " + meta.Installed.Format("2006-01-02")
+				if meta.Installed.IsZero() {
+					installed = "embedded"
+				}
+				fmt.Fprintf(out, "  %-16s %8d words, %s\n", dict.FullName(), meta.Words, installed)
 			}
 			if len(allDicts) == 0 {
-				fmt.Printf("  None\n")
+				fmt.Fprintf(out, "  None\n")
 			}
-
+			return nil
 		},
 	}
 	return download
 }
 
+// printMetadata prints the metadata and the load filter of a dictionary, if available
+func printMetadata(out io.Writer, config *core.Config, dict util.Dict, indent string) error {
+	meta, ok, err := util.LoadMetadata(config.Root, dict)
+	if err != nil {
+		return fmt.Errorf("failed to show dictionary: %w", err)
+	}
+	field := func(name string, value string) {
+		if value != "" {
+			fmt.Fprintf(out, "%s%-12s %s\n", indent, name+":", value)
+		}
+	}
+	if ok {
+		field("source", meta.Source)
+		field("license", meta.License)
+		field("description", meta.Description)
+		if !meta.Installed.IsZero() {
+			field("installed", meta.Installed.Format(time.RFC3339))
+			field("updated", meta.Updated.Format(time.RFC3339))
+		}
+		field("words", fmt.Sprint(meta.Words))
+		field("checksum", meta.Checksum)
+		field("encoding", meta.Encoding)
+		field("file", meta.File)
+		field("alphabet", meta.Alphabet)
+	}
+	if !dict.Filter.IsEmpty() {
+		field("filter", formatFilter(dict.Filter))
+	}
+	return nil
+}
+
 func setDictCommand(config *core.Config) *cobra.Command {
 	download := &cobra.Command{
-		Use:     "set",
-		Short:   "Set the default dictionary",
+		Use:   "set DICT",
+		Short: "Set the default dictionary",
+		Long: `Set the default dictionary.
+
+Use a dictionary group by prefixing its name with '@' (see: xwrd dict group).
+`,
 		Aliases: []string{"s"},
 		Args:    util.WrappedArgs(cobra.ExactArgs(1)),
-		Run: func(cmd *cobra.Command, args []string) {
-			dictionary := util.NewDict(args[0])
-			if !util.HasDictionary(dictionary) {
-				fmt.Printf("failed to set dictionary: dictionary %s is not installed.\nTry: xwrd dict install %[1]s", dictionary.FullName())
-				return
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			dictionaries, err := config.GetDicts(args)
+			if err != nil {
+				return fmt.Errorf("failed to set dictionary: %w", err)
+			}
+			for _, dictionary := range dictionaries {
+				if !util.HasDictionary(config.Root, dictionary) {
+					return fmt.Errorf("failed to set dictionary: %w: %s\nTry: xwrd dict install %[2]s", util.ErrNoDictionary, dictionary.FullName())
+				}
 			}
 
-			config.Dict = dictionary.FullName()
-			err := core.SaveConfig(*config)
+			name := args[0]
+			if !strings.HasPrefix(name, core.GroupPrefix) {
+				name = dictionaries[0].FullName()
+			}
+			config.Dict = name
+			err = core.SaveConfig(*config)
 			if err != nil {
-				fmt.Printf("failed to set dictionary: %s", err.Error())
-				return
+				return fmt.Errorf("failed to set dictionary: %w", err)
 			}
 
-			fmt.Printf("dictionary set to %s", dictionary.FullName())
+			fmt.Fprintf(out, "dictionary set to %s\n", name)
+			return nil
 		},
 	}
 	return download
 }
 
+func groupDictCommand(config *core.Config) *cobra.Command {
+	var remove bool
+
+	group := &cobra.Command{
+		Use:   "group NAME [DICT...]",
+		Short: "Define, show or remove dictionary groups",
+		Long: `Define, show or remove dictionary groups.
+
+Groups combine multiple dictionaries. Their words are merged for queries.
+Use groups in flag --dict or in command dict set by prefixing their name with '@'.
+
+Without dictionaries, shows the dictionaries of the group.
+
+Examples
+--------
+
+xwrd dict group english en/yawl en/names
+xwrd anagram --dict @english listen
+xwrd dict group english --remove
+`,
+		Aliases: []string{"g"},
+		Args:    util.WrappedArgs(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			name := strings.TrimPrefix(args[0], core.GroupPrefix)
+			members, ok := config.Groups[name]
+
+			if remove {
+				if !ok {
+					return fmt.Errorf("failed to remove group: %w: '%s%s'", core.ErrUnknownGroup, core.GroupPrefix, name)
+				}
+				if config.Dict == core.GroupPrefix+name {
+					return fmt.Errorf("failed to remove group: %s%s is the current dictionary. Set another one first", core.GroupPrefix, name)
+				}
+				delete(config.Groups, name)
+				if err := core.SaveConfig(*config); err != nil {
+					return fmt.Errorf("failed to remove group: %w", err)
+				}
+				fmt.Fprintf(out, "removed group %s%s\n", core.GroupPrefix, name)
+				return nil
+			}
+
+			if len(args) == 1 {
+				if !ok {
+					return fmt.Errorf("failed to show group: %w: '%s%s'", core.ErrUnknownGroup, core.GroupPrefix, name)
+				}
+				fmt.Fprintf(out, "%s%s: %s\n", core.GroupPrefix, name, strings.Join(members, ", "))
+				return nil
+			}
+
+			members = []string{}
+			for _, arg := range args[1:] {
+				dictionary := util.NewDict(arg)
+				if !util.HasDictionary(config.Root, dictionary) {
+					return fmt.Errorf("failed to set group: %w: %s", util.ErrNoDictionary, dictionary.FullName())
+				}
+				members = append(members, dictionary.FullName())
+			}
+			if config.Groups == nil {
+				config.Groups = map[string][]string{}
+			}
+			config.Groups[name] = members
+			if err := core.SaveConfig(*config); err != nil {
+				return fmt.Errorf("failed to set group: %w", err)
+			}
+			fmt.Fprintf(out, "set group %s%s: %s\n", core.GroupPrefix, name, strings.Join(members, ", "))
+			return nil
+		},
+	}
+	group.Flags().BoolVar(&remove, "remove", false, "Remove the group.")
+
+	return group
+}
+
 func installDictCommand(config *core.Config) *cobra.Command {
+	var from string
+	var file string
+	var encoding string
+	opts := util.DefaultDownloadOptions()
+
 	install := &cobra.Command{
-		Use:     "install DICT",
-		Short:   "Install dictionaries",
+		Use:   "install DICT",
+		Short: "Install dictionaries",
+		Long: `Install dictionaries.
+
+Installs dictionaries listed in the registry (see: xwrd dict list),
+or from a local file or URL given by flag --from.
+
+Gzip, zip and tar archives (also .tar.gz) are unpacked.
+Use flag --file to select the word list in archives with multiple files.
+
+Word lists are converted to UTF-8. Latin-1 and Windows-1252 are detected automatically.
+Use flag --encoding to declare the encoding of the word list.
+
+Examples
+--------
+
+xwrd dict install en/yawl
+xwrd dict install --from words.txt en/mylist
+xwrd dict install --from https://example.com/words.txt en/mylist
+xwrd dict install --from words.zip --file lists/words.txt --compress en/mylist
+`,
 		Aliases: []string{"i"},
 		Args:    util.WrappedArgs(cobra.ExactArgs(1)),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
 			dictionary := util.NewDict(args[0])
-			if util.HasDictionary(dictionary) {
-				fmt.Printf("failed to install dictionary: dictionary already exists")
-				return
+			if util.HasDictionary(config.Root, dictionary) {
+				return fmt.Errorf("failed to install dictionary: %w", util.ErrDictionaryExists)
 			}
 
-			lang, ok := util.Dictionaries[dictionary.Language]
-			if !ok {
-				fmt.Printf("failed to install dictionary: no dictionaries in language '%s'", dictionary.Language)
-				return
+			dictionary, err := dictSource(config, dictionary, from, file, encoding)
+			if err != nil {
+				return fmt.Errorf("failed to install dictionary: %w", err)
 			}
 
-			found := false
-			for _, dict := range lang {
-				if dict.Name == dictionary.Name {
-					dictionary = dict
-					found = true
-					break
-				}
+			fmt.Fprintf(out, "installing dictionary %s from %s...\n", dictionary.FullName(), dictionary.URL)
+			opts.Progress = downloadProgress(cmd.ErrOrStderr())
+			count, err := util.InstallDictionary(config.Root, dictionary, opts)
+			fmt.Fprintln(cmd.ErrOrStderr())
+			if err != nil {
+				return fmt.Errorf("failed to install dictionary: %w", err)
+			}
+			fmt.Fprintf(out, "installed dictionary %s with %d words\n", dictionary.FullName(), count)
+			return nil
+		},
+	}
+	install.Flags().StringVar(&from, "from", "", "Install from a local file or URL instead of the registry.")
+	install.Flags().StringVar(&file, "file", "", "Path or name of the word list inside an archive.")
+	install.Flags().StringVar(&encoding, "encoding", "", "Encoding of the word list, like latin-1 or windows-1252. Detected if not given.")
+	install.Flags().BoolVar(&opts.Compress, "compress", false, "Store the dictionary gzip-compressed.")
+	install.Flags().DurationVar(&opts.Timeout, "timeout", opts.Timeout, "Timeout for each download attempt. No timeout if 0.")
+	install.Flags().IntVar(&opts.Retries, "retries", opts.Retries, "Number of retries after failed download attempts.")
+
+	return install
+}
+
+func updateDictCommand(config *core.Config) *cobra.Command {
+	var from string
+	var file string
+	var encoding string
+	opts := util.DefaultDownloadOptions()
+
+	update := &cobra.Command{
+		Use:   "update DICT",
+		Short: "Update installed dictionaries",
+		Long: `Update installed dictionaries.
+
+Downloads the dictionary again, from the registry (see: xwrd dict list)
+or from a local file or URL given by flag --from.
+Dictionaries not in the registry are updated from the source they were installed from.
+The installed dictionary is only replaced if the content changed.
+
+Examples
+--------
+
+xwrd dict update en/yawl
+xwrd dict update --from https://example.com/words.txt en/mylist
+`,
+		Args: util.WrappedArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			dictionary := util.NewDict(args[0])
+			if !util.HasDictionary(config.Root, dictionary) {
+				return fmt.Errorf("failed to update dictionary: %w: %s", util.ErrNoDictionary, dictionary.FullName())
 			}
-			if !found {
-				fmt.Printf("failed to install dictionary: dictionary '%s' not found in language '%s'", dictionary.Name, dictionary.Language)
-				return
+
+			source, err := dictSource(config, dictionary, from, file, encoding)
+			if errors.Is(err, util.ErrUnknownDictionary) && from == "" {
+				source, err = recordedSource(config, dictionary, file, encoding, err)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to update dictionary: %w", err)
 			}
+			dictionary = source
 
-			fmt.Printf("installing dictionary %s/%s from %s...\n", dictionary.Language, dictionary.Name, dictionary.URL)
-			err := util.DownloadDictionary(dictionary)
+			fmt.Fprintf(out, "updating dictionary %s from %s...\n", dictionary.FullName(), dictionary.URL)
+			opts.Progress = downloadProgress(cmd.ErrOrStderr())
+			count, updated, err := util.UpdateDictionary(config.Root, dictionary, opts)
+			fmt.Fprintln(cmd.ErrOrStderr())
 			if err != nil {
-				fmt.Printf("failed to install dictionary: %s", err.Error())
-				return
+				return fmt.Errorf("failed to update dictionary: %w", err)
+			}
+			if !updated {
+				fmt.Fprintf(out, "dictionary %s is up to date, %d words\n", dictionary.FullName(), count)
+				return nil
 			}
+			fmt.Fprintf(out, "updated dictionary %s, now %d words\n", dictionary.FullName(), count)
+			return nil
 		},
 	}
-	return install
+	update.Flags().StringVar(&from, "from", "", "Update from a local file or URL instead of the registry.")
+	update.Flags().StringVar(&file, "file", "", "Path or name of the word list inside an archive.")
+	update.Flags().StringVar(&encoding, "encoding", "", "Encoding of the word list, like latin-1 or windows-1252. Detected if not given.")
+	update.Flags().DurationVar(&opts.Timeout, "timeout", opts.Timeout, "Timeout for each download attempt. No timeout if 0.")
+	update.Flags().IntVar(&opts.Retries, "retries", opts.Retries, "Number of retries after failed download attempts.")
+
+	return update
+}
+
+func removeDictCommand(config *core.Config) *cobra.Command {
+	remove := &cobra.Command{
+		Use:   "remove DICT",
+		Short: "Remove installed dictionaries",
+		Long: `Remove installed dictionaries.
+
+The currently set dictionary, or a dictionary of the current group, can't be removed.
+Set another dictionary first (see: xwrd dict set).
+Embedded starter dictionaries, like en/starter, can't be removed.
+`,
+		Aliases: []string{"rm"},
+		Args:    util.WrappedArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dictionary := util.NewDict(args[0])
+			if config.IsCurrent(dictionary) {
+				return fmt.Errorf("failed to remove dictionary: %s is the current dictionary. Set another one first", dictionary.FullName())
+			}
+
+			if err := util.RemoveDictionary(config.Root, dictionary); err != nil {
+				return fmt.Errorf("failed to remove dictionary: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "removed dictionary %s\n", dictionary.FullName())
+			return nil
+		},
+	}
+	return remove
+}
+
+func renameDictCommand(config *core.Config) *cobra.Command {
+	rename := &cobra.Command{
+		Use:   "rename DICT NEW",
+		Short: "Rename installed dictionaries",
+		Long: `Rename installed dictionaries.
+
+If the currently set dictionary or a dictionary in a group is renamed, the settings are updated.
+`,
+		Aliases: []string{"mv"},
+		Args:    util.WrappedArgs(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			dictionary := util.NewDict(args[0])
+			target := util.NewDict(args[1])
+
+			if err := util.RenameDictionary(config.Root, dictionary, target); err != nil {
+				return fmt.Errorf("failed to rename dictionary: %w", err)
+			}
+			fmt.Fprintf(out, "renamed dictionary %s to %s\n", dictionary.FullName(), target.FullName())
+
+			if !config.RenameDict(dictionary, target) {
+				return nil
+			}
+			if err := core.SaveConfig(*config); err != nil {
+				return fmt.Errorf("failed to rename dictionary: %w", err)
+			}
+			fmt.Fprintf(out, "updated settings for %s\n", target.FullName())
+			return nil
+		},
+	}
+	return rename
+}
+
+// dictSource sets the source of a dictionary from the registry, or from a local file or URL if from is given.
+// Arguments file and encoding override the registry entry if given.
+func dictSource(config *core.Config, dictionary util.Dict, from string, file string, encoding string) (util.Dict, error) {
+	if from != "" {
+		dictionary.URL = from
+	} else {
+		registry, err := util.LoadRegistry(config.Root)
+		if err != nil {
+			return dictionary, err
+		}
+		if !registry.HasLanguage(dictionary.Language) {
+			return dictionary, fmt.Errorf("%w: no dictionaries in language '%s'", util.ErrUnknownDictionary, dictionary.Language)
+		}
+		entry, ok := registry.Find(dictionary)
+		if !ok {
+			return dictionary, fmt.Errorf("%w: dictionary '%s' not found in language '%s'", util.ErrUnknownDictionary, dictionary.Name, dictionary.Language)
+		}
+		dictionary = entry
+	}
+	if file != "" {
+		dictionary.File = file
+	}
+	if encoding != "" {
+		dictionary.Encoding = encoding
+	}
+	return dictionary, nil
+}
+
+// recordedSource sets the source of a dictionary from its metadata.
+// Returns the given error if there is no recorded source.
+func recordedSource(config *core.Config, dictionary util.Dict, file string, encoding string, err error) (util.Dict, error) {
+	meta, ok, metaErr := util.LoadMetadata(config.Root, dictionary)
+	if metaErr != nil {
+		return dictionary, metaErr
+	}
+	if !ok || meta.Source == "" {
+		return dictionary, err
+	}
+	if file == "" {
+		file = meta.File
+	}
+	if encoding == "" {
+		encoding = meta.Encoding
+	}
+	return dictSource(config, dictionary, meta.Source, file, encoding)
 }
 
 func analyzeDictCommand(config *core.Config) *cobra.Command {
@@ -160,43 +536,29 @@ func analyzeDictCommand(config *core.Config) *cobra.Command {
 		Long: `Analyze dictionaries.
 
 Shows statistics about frequency of letters, word length, anagrams etc.
-Use without arguments to analyze the current dictionary`,
+Use without arguments to analyze the current dictionary or group`,
 		Aliases: []string{"a"},
 		Args:    util.WrappedArgs(cobra.MaximumNArgs(1)),
-		Run: func(cmd *cobra.Command, args []string) {
-			dictName := config.Dict
-			if len(args) > 0 {
-				dictName = args[0]
-			}
-			dictionary := util.NewDict(dictName)
-
-			if !util.HasDictionary(dictionary) {
-				fmt.Printf("failed to analyze dictionary: dictionary '%s' does not exist", dictName)
-				return
-			}
-
-			words, err := util.LoadDictionary(dictionary)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			words, _, _, err := loadWords(config, args, util.LoadFilter{})
 			if err != nil {
-				fmt.Printf("failed to analyze dictionary: %s", err.Error())
-				return
+				return fmt.Errorf("failed to analyze dictionary: %w", err)
 			}
 
-			analyze(words)
+			eng := engine.NewFromWords(words, engine.Options{Progress: progressBar(cmd.ErrOrStderr())})
+			eng.Build()
+
+			analyze(cmd.OutOrStdout(), eng)
+			return nil
 		},
 	}
 	return analyze
 }
 
-func analyze(words []string) {
-	tree := anagram.NewTree([]rune(anagram.Letters))
-	progress := make(chan int, 8)
-	go tree.AddWords(words, progress)
+func analyze(out io.Writer, eng *engine.Engine) {
+	tree := eng.Tree()
 
-	for pr := range progress {
-		bar := strings.Repeat("#", pr/2)
-		fmt.Fprintf(os.Stderr, "\rBuilding tree: [%-50s]", bar)
-	}
-	fmt.Fprintln(os.Stderr)
+	words := eng.Words()
 
 	numWords := len(words)
 	numNonAnagrams := len(tree.Leaves)
@@ -257,18 +619,18 @@ func analyze(words []string) {
 	maxAnagrams := 0
 	var maxLeafs []anagram.Leaf
 	for _, leaf := range tree.Leaves {
-		if len(leaf) > maxAnagrams {
+		if len(leaf.Words) > maxAnagrams {
 			maxLeafs = maxLeafs[:0]
 			maxLeafs = append(maxLeafs, leaf)
-			maxAnagrams = len(leaf)
-		} else if len(leaf) == maxAnagrams {
-			maxAnagrams = len(leaf)
+			maxAnagrams = len(leaf.Words)
+		} else if len(leaf.Words) == maxAnagrams {
+			maxAnagrams = len(leaf.Words)
 		}
 
-		for len(anagramsHist) <= len(leaf) {
+		for len(anagramsHist) <= len(leaf.Words) {
 			anagramsHist = append(anagramsHist, 0)
 		}
-		anagramsHist[len(leaf)]++
+		anagramsHist[len(leaf.Words)]++
 	}
 
 	allRunes := []string{}
@@ -277,27 +639,27 @@ func analyze(words []string) {
 	}
 	sort.Strings(allRunes)
 
-	fmt.Printf("\n")
-	fmt.Printf("Words  : %d (%d)\n\n", numWords, numNonAnagrams)
+	fmt.Fprintf(out, "\n")
+	fmt.Fprintf(out, "Words  : %d (%d)\n\n", numWords, numNonAnagrams)
 
-	fmt.Printf("Words length:\n")
+	fmt.Fprintf(out, "Words length:\n")
 	for i, l := range lengthHist {
 		if i == 0 {
 			continue
 		}
-		fmt.Printf("%2d: %8d\n", i, l)
+		fmt.Fprintf(out, "%2d: %8d\n", i, l)
 	}
 
 	if len(longestWords) > 10 {
-		fmt.Printf("Longest words: %s...\n\n", strings.Join(longestWords[:10], ", "))
+		fmt.Fprintf(out, "Longest words: %s...\n\n", strings.Join(longestWords[:10], ", "))
 	} else {
-		fmt.Printf("Longest words: %s\n\n", strings.Join(longestWords, ", "))
+		fmt.Fprintf(out, "Longest words: %s\n\n", strings.Join(longestWords, ", "))
 	}
 
-	fmt.Printf("Letters: max    total   percent    words   percent\n")
+	fmt.Fprintf(out, "Letters: max    total   percent    words   percent\n")
 	for _, r := range allRunes {
 		rn := []rune(r)[0]
-		fmt.Printf(
+		fmt.Fprintf(out,
 			"  %s %8d %8d  (%5.02f%%) %8d  (%5.02f%%)\n",
 			r, maxRunes[int(rn)], totalRunes[int(rn)],
 			100.0*float64(totalRunes[int(rn)])/float64(totalRuneCount),
@@ -306,16 +668,28 @@ func analyze(words []string) {
 		)
 	}
 
-	fmt.Printf("\n")
-	fmt.Printf("Anagram frequency:\n")
+	fmt.Fprintf(out, "\n")
+	fmt.Fprintf(out, "Anagram frequency:\n")
 	for i, l := range anagramsHist {
 		if i == 0 {
 			continue
 		}
-		fmt.Printf("%2d: %8d\n", i, l)
+		fmt.Fprintf(out, "%2d: %8d\n", i, l)
 	}
-	fmt.Printf("Most anagrams:\n")
+	fmt.Fprintf(out, "Most anagrams:\n")
 	for _, leaf := range maxLeafs {
-		fmt.Printf("%s\n", strings.Join(leaf, "  "))
+		fmt.Fprintf(out, "%s\n", strings.Join(leaf.Words, "  "))
+	}
+}
+
+func downloadProgress(out io.Writer) func(done, total int64) {
+	return func(done, total int64) {
+		if total <= 0 {
+			fmt.Fprintf(out, "\rDownloading: %.1f MB", float64(done)/1e6)
+			return
+		}
+		percent := int(100 * done / total)
+		bar := strings.Repeat("#", percent/2)
+		fmt.Fprintf(out, "\rDownloading:   [%-50s] %3d%%", bar, percent)
 	}
 }
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"errors"
+
+	"github.com/mlange-42/xwrd/core"
+	"github.com/mlange-42/xwrd/engine"
+	"github.com/mlange-42/xwrd/util"
+)
+
+// Exit codes of the CLI
+const (
+	// ExitSuccess indicates success, with results found
+	ExitSuccess = 0
+	// ExitError indicates an unspecified error
+	ExitError = 1
+	// ExitNoResults indicates success, but no results found
+	ExitNoResults = 2
+	// ExitUsage indicates invalid arguments or flags
+	ExitUsage = 3
+	// ExitNoDictionary indicates a dictionary that is not installed or not available, or an unknown dictionary group
+	ExitNoDictionary = 4
+	// ExitBadPattern indicates an invalid pattern
+	ExitBadPattern = 5
+	// ExitNetwork indicates a failed or corrupted download
+	ExitNetwork = 6
+)
+
+var (
+	// ErrNoResults is an error for queries without any results
+	ErrNoResults = errors.New("no results")
+)
+
+// ExitCode returns the process exit code for an error returned by a command
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitSuccess
+	case errors.Is(err, ErrNoResults):
+		return ExitNoResults
+	case errors.Is(err, util.ErrUsage):
+		return ExitUsage
+	case errors.Is(err, util.ErrNoDictionary), errors.Is(err, util.ErrUnknownDictionary), errors.Is(err, core.ErrUnknownGroup):
+		return ExitNoDictionary
+	case errors.Is(err, engine.ErrBadPattern):
+		return ExitBadPattern
+	case errors.Is(err, util.ErrDownload), errors.Is(err, util.ErrChecksum):
+		return ExitNetwork
+	default:
+		return ExitError
+	}
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mlange-42/xwrd/core"
+	"github.com/mlange-42/xwrd/engine"
+	"github.com/mlange-42/xwrd/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	tt := []struct {
+		title    string
+		err      error
+		expected int
+	}{
+		{title: "no error", err: nil, expected: ExitSuccess},
+		{title: "other error", err: errors.New("test"), expected: ExitError},
+		{title: "no results", err: ErrNoResults, expected: ExitNoResults},
+		{title: "usage", err: fmt.Errorf("%w: test", util.ErrUsage), expected: ExitUsage},
+		{title: "no dictionary", err: fmt.Errorf("test: %w", util.ErrNoDictionary), expected: ExitNoDictionary},
+		{title: "unknown dictionary", err: fmt.Errorf("test: %w", util.ErrUnknownDictionary), expected: ExitNoDictionary},
+		{title: "bad pattern", err: fmt.Errorf("test: %w", engine.ErrBadPattern), expected: ExitBadPattern},
+		{title: "download", err: fmt.Errorf("test: %w", util.ErrDownload), expected: ExitNetwork},
+		{title: "group", err: fmt.Errorf("test: %w", core.ErrUnknownGroup), expected: ExitNoDictionary},
+		{title: "checksum", err: fmt.Errorf("test: %w", util.ErrChecksum), expected: ExitNetwork},
+	}
+
+	for _, test := range tt {
+		assert.Equal(t, test.expected, ExitCode(test.err), "Wrong exit code in %s", test.title)
+	}
+}
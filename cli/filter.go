@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mlange-42/xwrd/core"
+	"github.com/mlange-42/xwrd/util"
+	"github.com/spf13/cobra"
+)
+
+// filterOptions are the options for load filters, selecting the words loaded from dictionaries
+type filterOptions struct {
+	filter  util.LoadFilter
+	lengths string
+}
+
+func (f *filterOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&f.filter.Lowercase, "lowercase", false, "Load only words without upper-case letters.")
+	cmd.Flags().StringVar(&f.lengths, "lengths", "", "Load only words in a range of lengths, like '3-15', '3-' or '-15'.")
+	cmd.Flags().BoolVar(&f.filter.NoDigits, "no-digits", false, "Don't load words containing digits.")
+	cmd.Flags().BoolVar(&f.filter.NoApostrophes, "no-apostrophes", false, "Don't load words containing apostrophes.")
+	cmd.Flags().StringVar(&f.filter.Exclude, "exclude", "", "Don't load words matching a regular expression.")
+}
+
+// parse parses and validates the load filter
+func (f *filterOptions) parse() (util.LoadFilter, error) {
+	if f.lengths != "" {
+		var err error
+		f.filter.MinLength, f.filter.MaxLength, err = util.ParseLengthRange(f.lengths)
+		if err != nil {
+			return util.LoadFilter{}, err
+		}
+	}
+	if err := f.filter.Validate(); err != nil {
+		return util.LoadFilter{}, err
+	}
+	return f.filter, nil
+}
+
+// formatFilter describes a load filter in a single line
+func formatFilter(filter util.LoadFilter) string {
+	parts := []string{}
+	if filter.Lowercase {
+		parts = append(parts, "lowercase")
+	}
+	if filter.MinLength > 0 || filter.MaxLength > 0 {
+		lengths := fmt.Sprintf("lengths %d-", filter.MinLength)
+		if filter.MaxLength > 0 {
+			lengths += fmt.Sprint(filter.MaxLength)
+		}
+		parts = append(parts, lengths)
+	}
+	if filter.NoDigits {
+		parts = append(parts, "no digits")
+	}
+	if filter.NoApostrophes {
+		parts = append(parts, "no apostrophes")
+	}
+	if filter.Exclude != "" {
+		parts = append(parts, fmt.Sprintf("exclude '%s'", filter.Exclude))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func filterDictCommand(config *core.Config) *cobra.Command {
+	var remove bool
+	op := filterOptions{}
+
+	filter := &cobra.Command{
+		Use:   "filter DICT",
+		Short: "Set, show or remove the load filter of a dictionary",
+		Long: `Set, show or remove the load filter of a dictionary.
+
+Load filters select the words that are loaded from a dictionary, e.g. for games.
+Filters are stored in the config, and are applied by all commands.
+Commands can apply further filters with the same flags.
+
+Without filter flags, shows the dictionary's filter.
+
+Examples
+--------
+
+xwrd dict filter en/yawl --lowercase --lengths 3-15 --no-apostrophes
+xwrd dict filter en/yawl --exclude "^x"
+xwrd dict filter en/yawl --remove
+`,
+		Args: util.WrappedArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			dictionary := util.NewDict(args[0])
+			name := dictionary.FullName()
+
+			if remove {
+				if _, ok := config.Filters[name]; !ok {
+					return fmt.Errorf("failed to remove filter: no filter for %s", name)
+				}
+				delete(config.Filters, name)
+				if err := core.SaveConfig(*config); err != nil {
+					return fmt.Errorf("failed to remove filter: %w", err)
+				}
+				fmt.Fprintf(out, "removed filter of %s\n", name)
+				return nil
+			}
+
+			if cmd.Flags().NFlag() == 0 {
+				fmt.Fprintf(out, "%s: %s\n", name, formatFilter(config.Filters[name]))
+				return nil
+			}
+
+			if !util.HasDictionary(config.Root, dictionary) {
+				return fmt.Errorf("failed to set filter: %w: %s", util.ErrNoDictionary, name)
+			}
+			loadFilter, err := op.parse()
+			if err != nil {
+				return util.UsageError(cmd, err)
+			}
+			if config.Filters == nil {
+				config.Filters = map[string]util.LoadFilter{}
+			}
+			config.Filters[name] = loadFilter
+			if err := core.SaveConfig(*config); err != nil {
+				return fmt.Errorf("failed to set filter: %w", err)
+			}
+			fmt.Fprintf(out, "set filter of %s: %s\n", name, formatFilter(loadFilter))
+			return nil
+		},
+	}
+	filter.Flags().BoolVar(&remove, "remove", false, "Remove the filter.")
+	op.addFlags(filter)
+
+	return filter
+}
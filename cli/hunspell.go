@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mlange-42/xwrd/core"
+	"github.com/mlange-42/xwrd/hunspell"
+	"github.com/mlange-42/xwrd/util"
+	"github.com/spf13/cobra"
+)
+
+func importHunspellCommand(config *core.Config) *cobra.Command {
+	importCmd := &cobra.Command{
+		Use:   "import-hunspell DIC AFF DICT",
+		Short: "Import Hunspell dictionaries",
+		Long: `Import Hunspell dictionaries.
+
+Reads a Hunspell dictionary (.dic) and affix file (.aff),
+expands all stems with their prefix and suffix rules to full word forms,
+and installs the resulting word list as a new dictionary.
+
+Examples
+--------
+
+xwrd dict import-hunspell de_DE.dic de_DE.aff de/hunspell
+`,
+		Args: util.WrappedArgs(cobra.ExactArgs(3)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dictionary := util.NewDict(args[2])
+			if util.HasDictionary(config.Root, dictionary) {
+				return fmt.Errorf("failed to import dictionary: %w: %s", util.ErrDictionaryExists, dictionary.FullName())
+			}
+
+			dic, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to import dictionary: %w", err)
+			}
+			aff, err := os.ReadFile(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to import dictionary: %w", err)
+			}
+
+			encoding := hunspell.Encoding(aff)
+			if aff, err = util.Decode(aff, encoding); err != nil {
+				return fmt.Errorf("failed to import dictionary: %w", err)
+			}
+			if dic, err = util.Decode(dic, encoding); err != nil {
+				return fmt.Errorf("failed to import dictionary: %w", err)
+			}
+
+			affixes, err := hunspell.ParseAffixFile(aff)
+			if err != nil {
+				return fmt.Errorf("failed to import dictionary: %w", err)
+			}
+			words, stems, err := affixes.Expand(dic)
+			if err != nil {
+				return fmt.Errorf("failed to import dictionary: %w", err)
+			}
+
+			dictionary.Encoding = encoding
+			dictionary.Description = fmt.Sprintf("Imported from Hunspell dictionary %s", args[0])
+			count, err := util.SaveDictionary(config.Root, dictionary, words)
+			if err != nil {
+				return fmt.Errorf("failed to import dictionary: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "imported dictionary %s with %d words from %d stems\n", dictionary.FullName(), count, stems)
+			return nil
+		},
+	}
+	return importCmd
+}
@@ -1,19 +1,20 @@
 package cli
 
 import (
-	"bufio"
 	"fmt"
-	"os"
-	"regexp"
-	"unicode/utf8"
 
 	"github.com/mlange-42/xwrd/core"
+	"github.com/mlange-42/xwrd/engine"
 	"github.com/mlange-42/xwrd/util"
 	"github.com/spf13/cobra"
 )
 
 func matchCommand(config *core.Config) *cobra.Command {
-	var dict string
+	var dicts []string
+	var showSources bool
+	var freq frequencyOptions
+	var load filterOptions
+	var cs caseOptions
 
 	match := &cobra.Command{
 		Use:   "match [WORDS...]",
@@ -37,30 +38,41 @@ a....b - find all words of length 6 stat start with 'a' and end with 'b'
 `,
 		Aliases: []string{"m"},
 		Args:    util.WrappedArgs(cobra.ArbitraryArgs),
-		Run: func(cmd *cobra.Command, args []string) {
-			dictionary := config.GetDict()
-			if dict != "" {
-				dictionary = util.NewDict(dict)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := freq.validate(); err != nil {
+				return util.UsageError(cmd, err)
 			}
-
-			words, err := util.LoadDictionary(dictionary)
+			if err := cs.validate(); err != nil {
+				return util.UsageError(cmd, err)
+			}
+			loadFilter, err := load.parse()
 			if err != nil {
-				fmt.Printf("failed to find matching words: %s", err.Error())
-				return
+				return util.UsageError(cmd, err)
+			}
+			words, sources, freqs, err := loadWords(config, dicts, loadFilter)
+			if err != nil {
+				return fmt.Errorf("failed to find matching words: %w", err)
+			}
+			if !showSources {
+				sources = nil
 			}
+			eng := engine.NewFromWords(words, cs.engineOptions(engine.Options{Frequencies: freqs}))
+			out := cmd.OutOrStdout()
 
 			interactive := len(args) == 0
 
+			var reader lineReader
+			if interactive {
+				reader = newLineReader(cmd, config.Root, nil)
+				defer reader.Close()
+			}
+
+			found := 0
 			for {
 				var text []string
 				if interactive {
-					fmt.Print("Enter a pattern: ")
-					var answer string
-					scanner := bufio.NewScanner(os.Stdin)
-					if scanner.Scan() {
-						answer = scanner.Text()
-					}
-					if len(answer) == 0 {
+					answer, err := reader.Prompt("Enter a pattern: ")
+					if err != nil || len(answer) == 0 {
 						break
 					}
 					text = []string{answer}
@@ -70,62 +82,40 @@ a....b - find all words of length 6 stat start with 'a' and end with 'b'
 
 				for _, word := range text {
 					if !interactive {
-						fmt.Printf("%s:\n", word)
+						fmt.Fprintf(out, "%s:\n", word)
 					}
 
-					pattern, err := createPattern(word)
+					pattern, err := cs.compile(word)
 					if err != nil {
-						fmt.Printf("failed to find matching words: %s", err.Error())
-						return
+						if !interactive {
+							return fmt.Errorf("failed to find matching words: %w", err)
+						}
+						fmt.Fprintf(cmd.ErrOrStderr(), "failed to find matching words: %s\n", err.Error())
+						continue
 					}
-					res := findWords(words, pattern)
+					res := eng.Match(pattern, freq.query())
 					for _, r := range res {
-						fmt.Println("  " + r)
+						fmt.Fprintln(out, "  "+formatWords([]string{r}, sources))
 					}
+					found += len(res)
 				}
 
 				if !interactive {
 					break
 				}
 			}
+
+			if !interactive && found == 0 {
+				return ErrNoResults
+			}
+			return nil
 		},
 	}
-	match.Flags().StringVarP(&dict, "dict", "d", "", "Path to the dictionary/word list to use.")
+	match.Flags().StringSliceVarP(&dicts, "dict", "d", []string{}, "Dictionaries to use, comma-separated or repeated. Prefix groups with '@'.")
+	match.Flags().BoolVar(&showSources, "sources", false, "Show the source dictionaries of words.")
+	freq.addFlags(match)
+	load.addFlags(match)
+	cs.addFlags(match)
 
 	return match
 }
-
-func createPattern(word string) (*regexp.Regexp, error) {
-	exp1, err := regexp.Compile("\\.+")
-	if err != nil {
-		return nil, err
-	}
-	exp2, err := regexp.Compile("\\*+")
-	if err != nil {
-		return nil, err
-	}
-	pattern := exp1.ReplaceAllStringFunc(
-		word,
-		func(m string) string {
-			return fmt.Sprintf("\\p{L}{%d}", utf8.RuneCountInString(m))
-		},
-	)
-	pattern = exp2.ReplaceAllStringFunc(
-		pattern,
-		func(m string) string {
-			return "\\p{L}*"
-		},
-	)
-	pattern = fmt.Sprintf("(?i)^%s$", pattern)
-	return regexp.Compile(pattern)
-}
-
-func findWords(words []string, pattern *regexp.Regexp) []string {
-	results := []string{}
-	for _, word := range words {
-		if pattern.MatchString(word) {
-			results = append(results, word)
-		}
-	}
-	return results
-}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mlange-42/xwrd/core"
+	"github.com/mlange-42/xwrd/util"
+	"github.com/spf13/cobra"
+)
+
+func addWordCommand(config *core.Config) *cobra.Command {
+	return overlayWordCommand(config, "add-word", "Add words to a dictionary",
+		`Add words to a dictionary.
+
+Added words are stored in the dictionary's overlay, and are kept when the dictionary is updated.
+Adding a blocked word un-blocks it. Use --undo to remove words from the added words.
+`,
+		overlayChange{
+			change: func(o *util.Overlay, words []string) []string { return o.AddWords(words...) },
+			undo:   func(o *util.Overlay, words []string) []string { return o.RemoveAdded(words...) },
+			done:   "added",
+			undone: "un-added",
+		})
+}
+
+func blockWordCommand(config *core.Config) *cobra.Command {
+	return overlayWordCommand(config, "block-word", "Block words of a dictionary",
+		`Block words of a dictionary.
+
+Blocked words are stored in the dictionary's overlay, and are kept when the dictionary is updated.
+Blocking an added word un-adds it. Use --undo to remove words from the blocked words.
+`,
+		overlayChange{
+			change: func(o *util.Overlay, words []string) []string { return o.BlockWords(words...) },
+			undo:   func(o *util.Overlay, words []string) []string { return o.RemoveBlocked(words...) },
+			done:   "blocked",
+			undone: "un-blocked",
+		})
+}
+
+// overlayChange describes how a command changes an overlay, and how it undoes the change.
+// Functions return the words that were actually changed.
+type overlayChange struct {
+	change func(*util.Overlay, []string) []string
+	undo   func(*util.Overlay, []string) []string
+	done   string
+	undone string
+}
+
+func overlayWordCommand(config *core.Config, use string, short string, long string, ch overlayChange) *cobra.Command {
+	var dict string
+	var undo bool
+
+	command := &cobra.Command{
+		Use:   use + " WORD...",
+		Short: short,
+		Long:  long,
+		Args:  util.WrappedArgs(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dictionary, err := singleDict(cmd, config, dict)
+			if err != nil {
+				return fmt.Errorf("failed to change dictionary: %w", err)
+			}
+			if !util.HasDictionary(config.Root, dictionary) {
+				return fmt.Errorf("failed to change dictionary: %w: %s", util.ErrNoDictionary, dictionary.FullName())
+			}
+
+			overlay, err := util.LoadOverlay(config.Root, dictionary)
+			if err != nil {
+				return fmt.Errorf("failed to change dictionary: %w", err)
+			}
+			done := ch.done
+			var changed []string
+			if undo {
+				changed = ch.undo(&overlay, args)
+				done = ch.undone
+			} else {
+				changed = ch.change(&overlay, args)
+			}
+			if err := util.SaveOverlay(config.Root, dictionary, overlay); err != nil {
+				return fmt.Errorf("failed to change dictionary: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(changed) == 0 {
+				fmt.Fprintf(out, "%s no words in %s\n", done, dictionary.FullName())
+				return nil
+			}
+			noun := "words"
+			if len(changed) == 1 {
+				noun = "word"
+			}
+			fmt.Fprintf(out, "%s %d %s in %s: %s\n", done, len(changed), noun, dictionary.FullName(), strings.Join(changed, ", "))
+			return nil
+		},
+	}
+	command.Flags().StringVarP(&dict, "dict", "d", "", "Dictionary to change. Default: the current dictionary.")
+	command.Flags().BoolVar(&undo, "undo", false, "Remove the words from the "+ch.done+" words instead.")
+
+	return command
+}
+
+func overlayCommand(config *core.Config) *cobra.Command {
+	overlay := &cobra.Command{
+		Use:   "overlay [DICT]",
+		Short: "Show added and blocked words of a dictionary",
+		Long: `Show added and blocked words of a dictionary.
+
+Use without arguments to show the overlay of the current dictionary.
+Change the overlay with commands add-word and block-word.
+`,
+		Args: util.WrappedArgs(cobra.MaximumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+			dictionary, err := singleDict(cmd, config, name)
+			if err != nil {
+				return fmt.Errorf("failed to show overlay: %w", err)
+			}
+			if !util.HasDictionary(config.Root, dictionary) {
+				return fmt.Errorf("failed to show overlay: %w: %s", util.ErrNoDictionary, dictionary.FullName())
+			}
+
+			overlay, err := util.LoadOverlay(config.Root, dictionary)
+			if err != nil {
+				return fmt.Errorf("failed to show overlay: %w", err)
+			}
+
+			fmt.Fprintf(out, "Overlay of %s\n", dictionary.FullName())
+			fmt.Fprintln(out, "Added:")
+			for _, word := range overlay.Add {
+				fmt.Fprintf(out, "  + %s\n", word)
+			}
+			if len(overlay.Add) == 0 {
+				fmt.Fprintln(out, "  None")
+			}
+			fmt.Fprintln(out, "Blocked:")
+			for _, word := range overlay.Block {
+				fmt.Fprintf(out, "  - %s\n", word)
+			}
+			if len(overlay.Block) == 0 {
+				fmt.Fprintln(out, "  None")
+			}
+			return nil
+		},
+	}
+	return overlay
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/mlange-42/xwrd/core"
+	"github.com/mlange-42/xwrd/engine"
+	"github.com/mlange-42/xwrd/util"
+	"github.com/spf13/cobra"
+)
+
+func phraseCommand(config *core.Config) *cobra.Command {
+	var dicts []string
+	var minLength uint
+	var filter string
+	var freq frequencyOptions
+	var load filterOptions
+	var ignore ignoreOptions
+
+	phrase := &cobra.Command{
+		Use:   "phrase PHRASE...",
+		Short: "Build phrase anagrams interactively",
+		Long: `Build phrase anagrams interactively.
+
+Shows partial anagrams of the remaining letters of the phrase.
+Pick a word to remove its letters from the remaining letters, until no letters are left.
+
+Enter #undo or #redo to undo or redo picking a word.
+Enter nothing or press Ctrl+C to quit.
+`,
+		Aliases: []string{"p"},
+		Args:    util.WrappedArgs(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := freq.validate(); err != nil {
+				return util.UsageError(cmd, err)
+			}
+			loadFilter, err := load.parse()
+			if err != nil {
+				return util.UsageError(cmd, err)
+			}
+			ignored, err := ignore.characters(config)
+			if err != nil {
+				return util.UsageError(cmd, err)
+			}
+			var pattern *regexp.Regexp
+			if filter != "" {
+				pattern, err = engine.Pattern(filter)
+				if err != nil {
+					return fmt.Errorf("failed to build phrase: %w", err)
+				}
+			}
+
+			words, _, freqs, err := loadWords(config, dicts, loadFilter)
+			if err != nil {
+				return fmt.Errorf("failed to build phrase: %w", err)
+			}
+
+			eng := engine.NewFromWords(words, engine.Options{Progress: progressBar(cmd.ErrOrStderr()), Frequencies: freqs, Ignored: ignored})
+			eng.Build()
+
+			out := cmd.OutOrStdout()
+			query := freq.query()
+			query.MinLength, query.Filter = minLength, pattern
+			builder := eng.NewPhraseBuilder(strings.Join(args, " "))
+
+			comp := completer{modes: []string{"#undo", "#redo"}}
+			reader := newLineReader(cmd, config.Root, comp.complete)
+			defer reader.Close()
+
+			for !builder.Done() {
+				printPhraseState(out, builder, query)
+
+				answer, err := reader.Prompt("Pick a word: ")
+				answer = strings.TrimSpace(answer)
+				if err != nil || len(answer) == 0 {
+					return nil
+				}
+
+				switch answer {
+				case "#undo", "#u":
+					if !builder.Undo() {
+						fmt.Fprintln(out, "nothing to undo")
+					}
+				case "#redo", "#r":
+					if !builder.Redo() {
+						fmt.Fprintln(out, "nothing to redo")
+					}
+				default:
+					if err := builder.Pick(answer); err != nil {
+						fmt.Fprintf(out, "failed to pick word: %s\n", err.Error())
+					}
+				}
+			}
+
+			fmt.Fprintf(out, "Phrase: %s\n", builder.Phrase())
+			return nil
+		},
+	}
+	phrase.Flags().StringSliceVarP(&dicts, "dict", "d", []string{}, "Dictionaries to use, comma-separated or repeated. Prefix groups with '@'.")
+	phrase.Flags().UintVarP(&minLength, "min-length", "l", 0, "Minimum word length for suggested words.")
+	phrase.Flags().StringVarP(&filter, "filter", "f", "", "Pattern for filtering suggested words.")
+	freq.addFlags(phrase)
+	load.addFlags(phrase)
+	ignore.addFlags(phrase)
+
+	return phrase
+}
+
+func printPhraseState(out io.Writer, builder *engine.PhraseBuilder, query engine.Query) {
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "Phrase:    %s\n", builder.Phrase())
+	fmt.Fprintf(out, "Remaining: %s\n", builder.Remaining())
+	printAnagrams(out, builder.Candidates(query), nil)
+}
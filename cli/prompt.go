@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mlange-42/xwrd/util"
+	"github.com/peterh/liner"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+)
+
+// lineReader reads lines of user input in interactive mode
+type lineReader interface {
+	// Prompt prints the prompt and reads a line. Returns io.EOF when input ends or is aborted.
+	Prompt(prompt string) (string, error)
+	// Close releases resources and persists the history
+	Close() error
+}
+
+// newLineReader creates a line editor with history and tab completion when reading from standard input,
+// and a plain line scanner otherwise. The history is stored in the given storage directory
+func newLineReader(cmd *cobra.Command, root string, complete func(line string) []string) lineReader {
+	if cmd.InOrStdin() != os.Stdin {
+		return &scanReader{scanner: bufio.NewScanner(cmd.InOrStdin()), out: cmd.OutOrStdout()}
+	}
+
+	state := liner.NewLiner()
+	state.SetCtrlCAborts(true)
+	if complete != nil {
+		state.SetCompleter(complete)
+	}
+	history := util.HistoryPath(root)
+	if file, err := os.Open(history); err == nil {
+		_, _ = state.ReadHistory(file)
+		file.Close()
+	}
+	return &editReader{state: state, history: history}
+}
+
+type scanReader struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+func (r *scanReader) Prompt(prompt string) (string, error) {
+	fmt.Fprint(r.out, prompt)
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+func (r *scanReader) Close() error {
+	return nil
+}
+
+type editReader struct {
+	state   *liner.State
+	history string
+}
+
+func (r *editReader) Prompt(prompt string) (string, error) {
+	line, err := r.state.Prompt(prompt)
+	if err != nil {
+		if errors.Is(err, liner.ErrPromptAborted) {
+			return "", io.EOF
+		}
+		return "", err
+	}
+	if strings.TrimSpace(line) != "" {
+		r.state.AppendHistory(line)
+	}
+	return line, nil
+}
+
+func (r *editReader) Close() error {
+	defer r.state.Close()
+
+	file, err := os.Create(r.history)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = r.state.WriteHistory(file)
+	return err
+}
+
+// completer completes modes, flag names and dictionary names in interactive mode
+type completer struct {
+	modes []string
+	flags map[string]bool
+	dicts bool
+	// root is the storage directory of the dictionaries, for completing dictionary names
+	root string
+}
+
+func (c *completer) complete(line string) []string {
+	results := []string{}
+	if strings.HasPrefix(line, "#dict ") {
+		if !c.dicts {
+			return results
+		}
+		dicts, err := util.AllDictionaries(c.root)
+		if err != nil {
+			return results
+		}
+		prefix := strings.TrimPrefix(line, "#dict ")
+		for _, name := range maps.Keys(dicts) {
+			if strings.HasPrefix(name, prefix) {
+				results = append(results, "#dict "+name)
+			}
+		}
+	} else if strings.HasPrefix(line, "#") {
+		for _, mode := range c.modes {
+			if strings.HasPrefix(mode, line) {
+				results = append(results, mode)
+			}
+		}
+		if c.dicts && strings.HasPrefix("#dict ", line) {
+			results = append(results, "#dict ")
+		}
+	} else if !strings.Contains(line, "=") {
+		for flag := range c.flags {
+			if len(flag) > 1 && strings.HasPrefix(flag, line) {
+				results = append(results, flag+"=")
+			}
+		}
+	}
+	sort.Strings(results)
+	return results
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompleter(t *testing.T) {
+	dir := setupRootDir(t)
+
+	comp := completer{
+		modes: []string{"#normal", "#partial", "#multi", "#match"},
+		flags: interactiveCommands,
+		dicts: true,
+		root:  dir,
+	}
+
+	tt := []struct {
+		title    string
+		line     string
+		expected []string
+	}{
+		{title: "modes", line: "#m", expected: []string{"#match", "#multi"}},
+		{title: "dict command", line: "#d", expected: []string{"#dict "}},
+		{title: "dict names", line: "#dict en/t", expected: []string{"#dict en/test"}},
+		{title: "all dict names", line: "#dict ", expected: []string{"#dict de/starter", "#dict de/test", "#dict en/starter", "#dict en/test"}},
+		{title: "flags", line: "m", expected: []string{"max-words=", "min-freq=", "min-length="}},
+		{title: "flag values", line: "filter=a", expected: []string{}},
+	}
+
+	for _, test := range tt {
+		assert.Equal(t, test.expected, comp.complete(test.line), "Wrong completion in %s", test.title)
+	}
+
+	comp = completer{modes: []string{"#normal"}, flags: interactiveCommands}
+	assert.Equal(t, []string{}, comp.complete("#dict e"), "Wrong completion without dictionaries")
+}
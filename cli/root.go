@@ -1,28 +1,65 @@
 package cli
 
 import (
+	"io"
+	"os"
+
 	"github.com/mlange-42/xwrd/core"
+	"github.com/mlange-42/xwrd/util"
 	"github.com/spf13/cobra"
 )
 
-// RootCommand sets up the CLI
-func RootCommand(config *core.Config, version string) *cobra.Command {
+// Streams are the input and output streams used by commands
+type Streams struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+}
+
+// StdStreams returns the standard input, output and error streams
+func StdStreams() Streams {
+	return Streams{
+		In:  os.Stdin,
+		Out: os.Stdout,
+		Err: os.Stderr,
+	}
+}
+
+// RootCommand sets up the CLI.
+// Argument rootDir is the storage directory for config and dictionaries, and is stored in the config.
+// Uses the config's storage directory if empty, or the default if that is empty, too.
+func RootCommand(config *core.Config, version string, streams Streams, rootDir string) *cobra.Command {
+	if rootDir != "" {
+		config.Root = rootDir
+	}
+	if config.Root == "" {
+		config.Root = util.DefaultRootDir()
+	}
+
 	root := &cobra.Command{
 		Use:           "xwrd",
 		Short:         "Words tool",
 		Version:       version,
 		SilenceUsage:  true,
 		SilenceErrors: true,
-		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			return nil
-		},
-		Run: func(cmd *cobra.Command, args []string) {
-			_ = cmd.Help()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
 		},
 	}
 
+	root.SetIn(streams.In)
+	root.SetOut(streams.Out)
+	root.SetErr(streams.Err)
+
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return util.UsageError(cmd, err)
+	})
+
 	root.AddCommand(anagramCommand(config))
 	root.AddCommand(matchCommand(config))
+	root.AddCommand(phraseCommand(config))
+	root.AddCommand(shellCommand(config))
+	root.AddCommand(tuiCommand(config))
 	root.AddCommand(dictCommand(config))
 
 	return root
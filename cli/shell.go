@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/mlange-42/xwrd/core"
+	"github.com/mlange-42/xwrd/engine"
+	"github.com/mlange-42/xwrd/util"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+)
+
+type shell struct {
+	config  *core.Config
+	filter  util.LoadFilter
+	ignored string
+	engines map[string]*engine.Engine
+	dict    string
+	match   bool
+	op      anagramOptions
+	out     io.Writer
+	errOut  io.Writer
+}
+
+func shellCommand(config *core.Config) *cobra.Command {
+	var dicts []string
+	var freq frequencyOptions
+	var load filterOptions
+	var ignore ignoreOptions
+
+	shellCmd := &cobra.Command{
+		Use:   "shell",
+		Short: "Interactive shell for anagrams and pattern matching",
+		Long: `Interactive shell for anagrams and pattern matching.
+
+Combines the interactive modes of commands anagram and match.
+Switch between modes and dictionaries without restarting.
+Loaded dictionaries are kept in memory.
+
+Enter ? in the shell for help.
+`,
+		Aliases: []string{"sh"},
+		Args:    util.WrappedArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := freq.validate(); err != nil {
+				return util.UsageError(cmd, err)
+			}
+			loadFilter, err := load.parse()
+			if err != nil {
+				return util.UsageError(cmd, err)
+			}
+			ignored, err := ignore.characters(config)
+			if err != nil {
+				return util.UsageError(cmd, err)
+			}
+			dictionary := config.Dict
+			if len(dicts) > 0 {
+				dictionary = strings.Join(dicts, ",")
+			}
+
+			sh := shell{
+				config:  config,
+				filter:  loadFilter,
+				ignored: ignored,
+				engines: map[string]*engine.Engine{},
+				op:      anagramOptions{frequencyOptions: freq},
+				out:     cmd.OutOrStdout(),
+				errOut:  cmd.ErrOrStderr(),
+			}
+			if err := sh.setDict(dictionary); err != nil {
+				return fmt.Errorf("failed to start shell: %w", err)
+			}
+
+			fmt.Fprintln(sh.out, "Find anagrams and words by pattern. Enter ? for help.")
+
+			comp := completer{
+				modes: []string{"#normal", "#partial", "#multi", "#match"},
+				flags: interactiveCommands,
+				dicts: true,
+				root:  config.Root,
+			}
+			reader := newLineReader(cmd, config.Root, comp.complete)
+			defer reader.Close()
+
+			for {
+				answer, err := reader.Prompt(fmt.Sprintf("%s %s> ", sh.dict, sh.mode()))
+				answer = strings.TrimSpace(answer)
+				if err != nil || len(answer) == 0 {
+					break
+				}
+				sh.process(answer)
+			}
+			return nil
+		},
+	}
+	shellCmd.Flags().StringSliceVarP(&dicts, "dict", "d", []string{}, "Dictionaries to use initially, comma-separated or repeated. Prefix groups with '@'.")
+	freq.addFlags(shellCmd)
+	load.addFlags(shellCmd)
+	ignore.addFlags(shellCmd)
+
+	return shellCmd
+}
+
+func (s *shell) mode() string {
+	if s.match {
+		return "#match"
+	}
+	return anagramMode(&s.op)
+}
+
+// setDict sets the dictionaries, given as comma-separated names of dictionaries and groups
+func (s *shell) setDict(dict string) error {
+	if _, ok := s.engines[dict]; !ok {
+		names := strings.Split(dict, ",")
+		for i, name := range names {
+			names[i] = strings.TrimSpace(name)
+		}
+		words, _, freqs, err := loadWords(s.config, names, s.filter)
+		if err != nil {
+			return err
+		}
+		s.engines[dict] = engine.NewFromWords(words, engine.Options{Progress: progressBar(s.errOut), Frequencies: freqs, Ignored: s.ignored})
+	}
+	s.dict = dict
+	return nil
+}
+
+func (s *shell) process(answer string) {
+	switch {
+	case answer == "?":
+		fmt.Fprint(s.out, s.help())
+	case answer == "#dict":
+		fmt.Fprintf(s.out, "dictionary %s\n", s.dict)
+	case strings.HasPrefix(answer, "#dict "):
+		dict := strings.TrimSpace(strings.TrimPrefix(answer, "#dict "))
+		if err := s.setDict(dict); err != nil {
+			fmt.Fprintf(s.errOut, "failed to set dictionary: %s\n", err.Error())
+			return
+		}
+		fmt.Fprintf(s.out, "switched to dictionary %s\n", s.dict)
+	case answer == "#match":
+		s.match = true
+		fmt.Fprintln(s.out, "switched to mode #match")
+	case setMode(answer, &s.op):
+		s.match = false
+		fmt.Fprintf(s.out, "switched to mode %s\n", s.mode())
+	default:
+		if str, ok := interactiveFlags(answer, &s.op, interactiveCommands); ok {
+			fmt.Fprintln(s.out, str)
+			return
+		}
+		s.query(answer)
+	}
+}
+
+func (s *shell) query(word string) {
+	eng := s.engines[s.dict]
+	if s.match {
+		pattern, err := engine.Pattern(word)
+		if err != nil {
+			fmt.Fprintf(s.errOut, "failed to find matching words: %s\n", err.Error())
+			return
+		}
+		for _, r := range eng.Match(pattern, s.op.query()) {
+			fmt.Fprintln(s.out, "  "+r)
+		}
+		return
+	}
+	if s.op.multi {
+		printMulti(s.out, eng.MultiAnagrams(word, s.op.query()), nil)
+	} else if s.op.partial {
+		printAnagrams(s.out, eng.PartialAnagrams(word, s.op.query()), nil)
+	} else {
+		printAnagrams(s.out, eng.Anagrams(word, s.op.query()), nil)
+	}
+}
+
+func (s *shell) help() string {
+	loaded := maps.Keys(s.engines)
+	sort.Strings(loaded)
+
+	sb := strings.Builder{}
+	fmt.Fprintln(&sb, "")
+	fmt.Fprintln(&sb, "To change the mode, enter #normal, #partial, #multi or #match.")
+	fmt.Fprintf(&sb, "Current mode: %s\n", s.mode())
+	fmt.Fprintln(&sb, "")
+	fmt.Fprintln(&sb, "To change the dictionary, enter #dict and the dictionary's name, like '#dict en/yawl'")
+	fmt.Fprintln(&sb, "Combine dictionaries like '#dict en/yawl,en/names', or use a group like '#dict @english'")
+	fmt.Fprintf(&sb, "Current dictionary: %s (loaded: %s)\n", s.dict, strings.Join(loaded, "; "))
+	fmt.Fprintln(&sb, "")
+	writeFlagsHelp(&sb, &s.op, s.mode())
+	fmt.Fprintln(&sb, "")
+	fmt.Fprintln(&sb, "To quit, enter nothing or press Ctrl+C")
+	return sb.String()
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mlange-42/xwrd/core"
+	"github.com/mlange-42/xwrd/engine"
+	"github.com/mlange-42/xwrd/tui"
+	"github.com/mlange-42/xwrd/util"
+	"github.com/spf13/cobra"
+)
+
+func tuiCommand(config *core.Config) *cobra.Command {
+	var dicts []string
+	var freq frequencyOptions
+	var load filterOptions
+	var ignore ignoreOptions
+
+	tuiCmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Full-screen terminal UI with live results",
+		Long: `Full-screen terminal UI with live results.
+
+Shows anagrams or matching words while typing.
+Use Tab to switch between the input, filter and unknown fields, and F2 to switch the mode.
+`,
+		Aliases: []string{"t"},
+		Args:    util.WrappedArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := freq.validate(); err != nil {
+				return util.UsageError(cmd, err)
+			}
+			loadFilter, err := load.parse()
+			if err != nil {
+				return util.UsageError(cmd, err)
+			}
+			ignored, err := ignore.characters(config)
+			if err != nil {
+				return util.UsageError(cmd, err)
+			}
+			words, _, freqs, err := loadWords(config, dicts, loadFilter)
+			if err != nil {
+				return fmt.Errorf("failed to start terminal UI: %w", err)
+			}
+
+			eng := engine.NewFromWords(words, engine.Options{Progress: progressBar(cmd.ErrOrStderr()), Frequencies: freqs, Ignored: ignored})
+			eng.Build()
+
+			screen, err := tcell.NewScreen()
+			if err != nil {
+				return fmt.Errorf("failed to start terminal UI: %w", err)
+			}
+			if err = screen.Init(); err != nil {
+				return fmt.Errorf("failed to start terminal UI: %w", err)
+			}
+			defer screen.Fini()
+
+			return tui.New(screen, eng, freq.query()).Run()
+		},
+	}
+	tuiCmd.Flags().StringSliceVarP(&dicts, "dict", "d", []string{}, "Dictionaries to use, comma-separated or repeated. Prefix groups with '@'.")
+	freq.addFlags(tuiCmd)
+	load.addFlags(tuiCmd)
+	ignore.addFlags(tuiCmd)
+
+	return tuiCmd
+}
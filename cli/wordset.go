@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/mlange-42/xwrd/core"
+	"github.com/mlange-42/xwrd/util"
+	"github.com/spf13/cobra"
+)
+
+func diffDictCommand(config *core.Config) *cobra.Command {
+	return wordSetCommand(config, "diff", "Words contained in dictionary A, but not in B",
+		util.Difference)
+}
+
+func unionDictCommand(config *core.Config) *cobra.Command {
+	return wordSetCommand(config, "union", "Words contained in dictionary A or B, or in both",
+		func(a, b []string) []string { return util.Union(a, b) })
+}
+
+func intersectDictCommand(config *core.Config) *cobra.Command {
+	return wordSetCommand(config, "intersect", "Words contained in both dictionaries A and B",
+		util.Intersection)
+}
+
+func wordSetCommand(config *core.Config, use string, short string, operation func(a, b []string) []string) *cobra.Command {
+	var outDict string
+	var printWords bool
+
+	command := &cobra.Command{
+		Use:   use + " A B",
+		Short: short,
+		Long: short + `.
+
+A and B can be installed dictionaries or plain word list files.
+Prints the number of words, and optionally saves the result as a new dictionary.
+
+Examples
+--------
+
+xwrd dict ` + use + ` de/enz de/custom
+xwrd dict ` + use + ` de/enz words.txt --out de/result
+`,
+		Args: util.WrappedArgs(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+
+			var target util.Dict
+			if outDict != "" {
+				target = util.NewDict(outDict)
+				if util.HasDictionary(config.Root, target) {
+					return fmt.Errorf("failed to %s dictionaries: %w: %s", use, util.ErrDictionaryExists, target.FullName())
+				}
+			}
+
+			a, freqsA, err := util.LoadWordListFrequencies(config.Root, args[0])
+			if err != nil {
+				return fmt.Errorf("failed to %s dictionaries: %w", use, err)
+			}
+			b, freqsB, err := util.LoadWordListFrequencies(config.Root, args[1])
+			if err != nil {
+				return fmt.Errorf("failed to %s dictionaries: %w", use, err)
+			}
+			result := operation(a, b)
+
+			if printWords {
+				for _, word := range result {
+					fmt.Fprintln(out, "  "+word)
+				}
+			}
+			fmt.Fprintf(out, "%-10s %8d words  (%s)\n", "A:", len(a), args[0])
+			fmt.Fprintf(out, "%-10s %8d words  (%s)\n", "B:", len(b), args[1])
+			fmt.Fprintf(out, "%-10s %8d words\n", use+":", len(result))
+
+			if outDict == "" {
+				return nil
+			}
+			target.Description = fmt.Sprintf("%s of %s and %s", use, args[0], args[1])
+			freqs := util.Frequencies{}
+			freqs.Merge(freqsA)
+			freqs.Merge(freqsB)
+			count, err := util.SaveDictionaryFrequencies(config.Root, target, result, freqs)
+			if err != nil {
+				return fmt.Errorf("failed to %s dictionaries: %w", use, err)
+			}
+			fmt.Fprintf(out, "saved dictionary %s with %d words\n", target.FullName(), count)
+			return nil
+		},
+	}
+	command.Flags().StringVarP(&outDict, "out", "o", "", "Save the result as a new dictionary.")
+	command.Flags().BoolVarP(&printWords, "print", "p", false, "Print the resulting words.")
+
+	return command
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
 
+	"github.com/mlange-42/xwrd/anagram"
 	"github.com/mlange-42/xwrd/util"
 	"gopkg.in/yaml.v3"
 )
@@ -13,21 +15,148 @@ import (
 var (
 	// ErrNoConfig is an error for no config file available
 	ErrNoConfig = errors.New("no config file")
+	// ErrUnknownGroup is an error for dictionary groups that are not defined
+	ErrUnknownGroup = errors.New("unknown dictionary group")
+	// ErrGroup is an error for dictionary groups given where a single dictionary is required
+	ErrGroup = errors.New("a single dictionary is required, not a group")
+	// ErrUnknownIgnored is an error for unknown names of ignored characters
+	ErrUnknownIgnored = errors.New("unknown ignored characters")
 )
 
+// IgnoredNames are the names of characters that can be ignored in anagrams. See IgnoredCharacters
+var IgnoredNames = []string{"spaces", "hyphens", "apostrophes", "periods"}
+
+// IgnoredCharacters are the characters that can be ignored in anagrams, by name
+var IgnoredCharacters = map[string]string{
+	"spaces":      " ",
+	"hyphens":     "-",
+	"apostrophes": "'’",
+	"periods":     ".",
+}
+
+// GroupPrefix marks names of dictionary groups, like in '@english'
+const GroupPrefix = "@"
+
 // Config for track
 type Config struct {
-	Dict string `yaml:"dict"`
+	Dict   string              `yaml:"dict"`
+	Groups map[string][]string `yaml:"groups,omitempty"`
+	// Filters are the load filters of dictionaries, by full dictionary name
+	Filters map[string]util.LoadFilter `yaml:"filters,omitempty"`
+	// Ignore are the names of characters ignored in anagrams, like "spaces". See IgnoredNames
+	Ignore []string `yaml:"ignore,omitempty"`
+	// Root is the storage directory of the config and dictionaries. Not saved
+	Root string `yaml:"-"`
+}
+
+// GetDict resolves the name of a single dictionary, with its load filter.
+// Resolves the current dictionary if the name is empty.
+// Returns an ErrGroup if the name, or the current selection, is a group.
+func (c *Config) GetDict(name string) (util.Dict, error) {
+	if name == "" {
+		name = c.Dict
+	}
+	if strings.HasPrefix(name, GroupPrefix) {
+		return util.Dict{}, fmt.Errorf("%w: '%s'", ErrGroup, name)
+	}
+	dicts, err := c.GetDicts([]string{name})
+	if err != nil {
+		return util.Dict{}, err
+	}
+	return dicts[0], nil
+}
+
+// GetDicts resolves names of dictionaries and groups to dictionaries, with their load filters.
+// Groups are referenced by their name prefixed with '@'.
+// Resolves the current dictionary or group if no names are given.
+func (c *Config) GetDicts(names []string) ([]util.Dict, error) {
+	if len(names) == 0 {
+		names = []string{c.Dict}
+	}
+	dicts := []util.Dict{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		members := []string{name}
+		if strings.HasPrefix(name, GroupPrefix) {
+			group, ok := c.Groups[strings.TrimPrefix(name, GroupPrefix)]
+			if !ok {
+				return nil, fmt.Errorf("%w: '%s'", ErrUnknownGroup, name)
+			}
+			members = group
+		}
+		for _, member := range members {
+			dict := util.NewDict(member)
+			if !seen[dict.FullName()] {
+				seen[dict.FullName()] = true
+				dict.Filter = c.Filters[dict.FullName()]
+				dicts = append(dicts, dict)
+			}
+		}
+	}
+	return dicts, nil
 }
 
-// GetDict return the current dictionary
-func (c *Config) GetDict() util.Dict {
-	return util.NewDict(c.Dict)
+// Ignored resolves names of characters ignored in anagrams, like "spaces" and "hyphens", to the characters.
+// Uses the names from the config if no names are given, and anagram.DefaultIgnored if there are none.
+func (c *Config) Ignored(names []string) (string, error) {
+	if len(names) == 0 {
+		names = c.Ignore
+	}
+	if len(names) == 0 {
+		return anagram.DefaultIgnored, nil
+	}
+	chars := ""
+	for _, name := range names {
+		ch, ok := IgnoredCharacters[strings.TrimSpace(name)]
+		if !ok {
+			return "", fmt.Errorf("%w: '%s'. Use any of: %s", ErrUnknownIgnored, name, strings.Join(IgnoredNames, ", "))
+		}
+		chars += ch
+	}
+	return chars, nil
+}
+
+// IsCurrent checks whether a dictionary is the current dictionary, or part of the current group
+func (c *Config) IsCurrent(dict util.Dict) bool {
+	current, err := c.GetDicts(nil)
+	if err != nil {
+		return false
+	}
+	for _, d := range current {
+		if d.FullName() == dict.FullName() {
+			return true
+		}
+	}
+	return false
+}
+
+// RenameDict replaces a dictionary by another one, as the current dictionary, in all groups and in the load filters.
+// Returns whether the config was changed.
+func (c *Config) RenameDict(dict util.Dict, target util.Dict) bool {
+	changed := false
+	if current := util.NewDict(c.Dict); !strings.HasPrefix(c.Dict, GroupPrefix) && current.FullName() == dict.FullName() {
+		c.Dict = target.FullName()
+		changed = true
+	}
+	if filter, ok := c.Filters[dict.FullName()]; ok {
+		delete(c.Filters, dict.FullName())
+		c.Filters[target.FullName()] = filter
+		changed = true
+	}
+	for _, members := range c.Groups {
+		for i, member := range members {
+			if d := util.NewDict(member); d.FullName() == dict.FullName() {
+				members[i] = target.FullName()
+				changed = true
+			}
+		}
+	}
+	return changed
 }
 
-// LoadConfig loads the track config, or creates and saves default settings
-func LoadConfig() (Config, error) {
-	conf, err := tryLoadConfig()
+// LoadConfig loads the track config from a storage directory, or creates and saves default settings
+func LoadConfig(root string) (Config, error) {
+	conf, err := tryLoadConfig(root)
 	if err == nil {
 		return conf, nil
 	}
@@ -36,7 +165,8 @@ func LoadConfig() (Config, error) {
 	}
 
 	conf = Config{
-		Dict: "en/yawl",
+		Dict: "en/" + util.StarterName,
+		Root: root,
 	}
 
 	err = SaveConfig(conf)
@@ -47,13 +177,13 @@ func LoadConfig() (Config, error) {
 	return conf, nil
 }
 
-func tryLoadConfig() (Config, error) {
-	file, err := ioutil.ReadFile(util.ConfigPath())
+func tryLoadConfig(root string) (Config, error) {
+	file, err := ioutil.ReadFile(util.ConfigPath(root))
 	if err != nil {
 		return Config{}, ErrNoConfig
 	}
 
-	var conf Config
+	conf := Config{Root: root}
 
 	if err := yaml.Unmarshal(file, &conf); err != nil {
 		return Config{}, err
@@ -66,13 +196,13 @@ func tryLoadConfig() (Config, error) {
 	return conf, nil
 }
 
-// SaveConfig saves the given config to it's default location
+// SaveConfig saves the given config to it's storage directory
 func SaveConfig(conf Config) error {
 	if err := CheckConfig(&conf); err != nil {
 		return err
 	}
 
-	path := util.ConfigPath()
+	path := util.ConfigPath(conf.Root)
 
 	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
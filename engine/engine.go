@@ -0,0 +1,360 @@
+// Package engine provides word matching and anagram search, independent of the command line interface.
+package engine
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mlange-42/xwrd/anagram"
+	"github.com/mlange-42/xwrd/util"
+)
+
+// Options for creating an Engine
+type Options struct {
+	// Letters used for the anagram tree. Defaults to anagram.Letters
+	Letters string
+	// Progress is called with the percentage of words added while building the anagram tree. Optional.
+	Progress func(percent int)
+	// Frequencies of words, for sorting and filtering results by frequency. Optional.
+	// Higher frequencies denote more familiar words. They are stored with the words in the anagram tree's leaves
+	Frequencies map[string]int
+	// CaseSensitive distinguishes upper- and lower-case letters in anagrams. See also CaseSensitivePattern
+	CaseSensitive bool
+	// ProperNouns selects whether words starting with an upper-case letter are used
+	ProperNouns ProperNouns
+	// Ignored are the characters ignored in words and queries for anagrams, like spaces in "ice cream".
+	// Results show words in their original form. Defaults to anagram.DefaultIgnored
+	Ignored string
+}
+
+// ProperNouns selects how words starting with an upper-case letter are handled
+type ProperNouns int
+
+// Handling of proper nouns
+const (
+	// ProperNounsInclude uses all words
+	ProperNounsInclude ProperNouns = iota
+	// ProperNounsExclude uses only words not starting with an upper-case letter
+	ProperNounsExclude
+	// ProperNounsOnly uses only words starting with an upper-case letter
+	ProperNounsOnly
+)
+
+// Query holds settings for anagram searches
+type Query struct {
+	// MinLength is the minimum word length for partial and multi-anagrams
+	MinLength uint
+	// MaxWords is the word count limit for multi-anagrams. 0 means no limit
+	MaxWords uint
+	// MinUnknown is the minimum number of unknown/open letters. Not supported for multi-anagrams
+	MinUnknown uint
+	// MaxUnknown is the maximum number of unknown/open letters. Not supported for multi-anagrams
+	MaxUnknown uint
+	// Filter is an optional pattern results must match. See Pattern
+	Filter *regexp.Regexp
+	// MinFreq is the minimum frequency of words. Words without a frequency are excluded. 0 means no limit
+	MinFreq int
+	// SortByFreq sorts results by word frequency, the most frequent words first
+	SortByFreq bool
+}
+
+// Anagram is a set of words consisting of the same letters
+type Anagram struct {
+	Words []string
+	// Added holds the letters added for unknown/open letters
+	Added []rune
+	// Frequencies holds the frequencies of the words. Nil if the engine has no frequencies
+	Frequencies []int
+}
+
+// MultiAnagram is a combination of anagram sets that together consist of the letters of the query
+type MultiAnagram struct {
+	Words [][]string
+}
+
+// Engine holds a word list and the anagram tree built from it
+type Engine struct {
+	options Options
+	words   []string
+	freqs   []int
+	tree    *anagram.Tree
+}
+
+// New creates an Engine from a reader providing one word per line
+func New(reader io.Reader, opts Options) (*Engine, error) {
+	words, err := ReadWords(reader)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromWords(words, opts), nil
+}
+
+// NewFromWords creates an Engine from a slice of words.
+// Words are selected according to the proper nouns option.
+func NewFromWords(words []string, opts Options) *Engine {
+	if opts.Letters == "" {
+		opts.Letters = anagram.Letters
+	}
+	if opts.Ignored == "" {
+		opts.Ignored = anagram.DefaultIgnored
+	}
+	if opts.ProperNouns != ProperNounsInclude {
+		selected := make([]string, 0, len(words))
+		for _, word := range words {
+			if IsProperNoun(word) == (opts.ProperNouns == ProperNounsOnly) {
+				selected = append(selected, word)
+			}
+		}
+		words = selected
+	}
+	var freqs []int
+	if len(opts.Frequencies) > 0 {
+		freqs = make([]int, len(words))
+		for i, word := range words {
+			freqs[i] = opts.Frequencies[word]
+		}
+	}
+	opts.Frequencies = nil
+	return &Engine{
+		options: opts,
+		words:   words,
+		freqs:   freqs,
+	}
+}
+
+// ReadWords reads one word per line, skipping empty lines
+func ReadWords(reader io.Reader) ([]string, error) {
+	words := []string{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		word := scanner.Text()
+		if len(word) == 0 {
+			continue
+		}
+		words = append(words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+// IsProperNoun checks whether a word starts with an upper-case letter
+func IsProperNoun(word string) bool {
+	first, _ := utf8.DecodeRuneInString(word)
+	return unicode.IsUpper(first)
+}
+
+// Words returns the engine's word list
+func (e *Engine) Words() []string {
+	return e.words
+}
+
+// Tree returns the engine's anagram tree, and builds it if necessary
+func (e *Engine) Tree() *anagram.Tree {
+	e.Build()
+	return e.tree
+}
+
+// Build builds the anagram tree, if not already done.
+// Anagram queries call it implicitly.
+func (e *Engine) Build() {
+	if e.tree != nil {
+		return
+	}
+	var tree anagram.Tree
+	if e.options.CaseSensitive {
+		tree = anagram.NewCaseSensitiveTree([]rune(e.options.Letters))
+	} else {
+		tree = anagram.NewTree([]rune(e.options.Letters))
+	}
+	tree.SetIgnored(e.options.Ignored)
+	progress := make(chan int, 8)
+	go tree.AddWordsFrequencies(e.words, e.freqs, progress)
+
+	for pr := range progress {
+		if e.options.Progress != nil {
+			e.options.Progress(pr)
+		}
+	}
+	e.tree = &tree
+}
+
+// Anagrams finds full anagrams of a word
+func (e *Engine) Anagrams(word string, q Query) []Anagram {
+	leaves := e.Tree().AnagramsWithUnknown(word, q.MinUnknown, q.MaxUnknown)
+	return e.toAnagrams(word, leaves, q)
+}
+
+// PartialAnagrams finds partial anagrams of a word, i.e. words using a subset of its letters
+func (e *Engine) PartialAnagrams(word string, q Query) []Anagram {
+	leaves := e.Tree().PartialAnagramsWithUnknown(word, q.MinLength, q.MinUnknown, q.MaxUnknown)
+	return e.toAnagrams(word, leaves, q)
+}
+
+// MultiAnagrams finds combinations of partial anagrams that together use all letters of a word
+func (e *Engine) MultiAnagrams(word string, q Query) []MultiAnagram {
+	ana := e.Tree().MultiAnagrams(word, q.MaxWords, q.MinLength, false)
+
+	results := []MultiAnagram{}
+	// Combinations are only as familiar as their least familiar word
+	minFreqs := []int{}
+	for _, res := range ana {
+		found := q.Filter == nil
+		foundIndex := -1
+		if q.Filter != nil {
+		FindMatch:
+			for b, block := range res {
+				for _, word := range block.Words {
+					if q.Filter.MatchString(word) {
+						found = true
+						foundIndex = b
+						break FindMatch
+					}
+				}
+			}
+		}
+		if !found {
+			continue
+		}
+
+		multi := MultiAnagram{Words: make([][]string, len(res))}
+		minFreq := 0
+		for b, block := range res {
+			if b == foundIndex {
+				block = filter(block, q.Filter)
+			}
+			block = e.rank(block, q)
+			if len(block.Words) == 0 {
+				found = false
+				break
+			}
+			multi.Words[b] = block.Words
+			if b == 0 || block.Frequency(0) < minFreq {
+				minFreq = block.Frequency(0)
+			}
+		}
+		if !found {
+			continue
+		}
+		results = append(results, multi)
+		minFreqs = append(minFreqs, minFreq)
+	}
+
+	if q.SortByFreq {
+		sort.Stable(multiByFrequency{results, minFreqs})
+	}
+	return results
+}
+
+// Match finds all words matching a pattern. See Pattern.
+// Of the query, only the frequency settings are used.
+func (e *Engine) Match(pattern *regexp.Regexp, q Query) []string {
+	words := anagram.Leaf{Words: e.words, Frequencies: e.freqs}
+	return e.rank(filter(words, pattern), q).Words
+}
+
+func (e *Engine) toAnagrams(word string, leaves []anagram.Leaf, q Query) []Anagram {
+	ignoreCase := !e.options.CaseSensitive
+	tree := e.Tree()
+	runes := util.UniqueRunes(tree.Strip(word), ignoreCase)
+	tempRunes := make(map[rune]int, len(runes))
+
+	results := []Anagram{}
+	for _, leaf := range leaves {
+		leaf = e.rank(filter(leaf, q.Filter), q)
+		if len(leaf.Words) == 0 {
+			continue
+		}
+		ana := Anagram{Words: leaf.Words, Frequencies: leaf.Frequencies}
+		if q.MaxUnknown > 0 {
+			for k := range tempRunes {
+				delete(tempRunes, k)
+			}
+			for k, v := range runes {
+				tempRunes[k] = v
+			}
+			ana.Added = util.FindAdditions(tempRunes, tree.Strip(leaf.Words[0]), ignoreCase)
+		}
+		results = append(results, ana)
+	}
+	e.sortAnagrams(results, q)
+	return results
+}
+
+// sortAnagrams sorts anagrams by the frequency of their most frequent word, if requested by the query.
+// Expects the words of each anagram to be ranked already.
+func (e *Engine) sortAnagrams(anagrams []Anagram, q Query) {
+	if !q.SortByFreq || e.freqs == nil {
+		return
+	}
+	sort.SliceStable(anagrams, func(i, j int) bool {
+		return anagrams[i].Frequencies[0] > anagrams[j].Frequencies[0]
+	})
+}
+
+// rank removes words below the query's minimum frequency, and sorts words by frequency if requested.
+// The given leaf is not modified.
+func (e *Engine) rank(leaf anagram.Leaf, q Query) anagram.Leaf {
+	if q.MinFreq > 0 {
+		leaf = selectWords(leaf, func(word string, freq int) bool { return freq >= q.MinFreq })
+	} else if q.SortByFreq {
+		leaf = selectWords(leaf, func(word string, freq int) bool { return true })
+	}
+	if q.SortByFreq && leaf.Frequencies != nil {
+		sort.Stable(byFrequency(leaf))
+	}
+	return leaf
+}
+
+func filter(leaf anagram.Leaf, pattern *regexp.Regexp) anagram.Leaf {
+	if pattern == nil {
+		return leaf
+	}
+	return selectWords(leaf, func(word string, freq int) bool { return pattern.MatchString(word) })
+}
+
+// selectWords returns a copy of a leaf with the words, and their frequencies, the predicate is true for
+func selectWords(leaf anagram.Leaf, keep func(word string, freq int) bool) anagram.Leaf {
+	result := anagram.Leaf{Words: []string{}}
+	if leaf.Frequencies != nil {
+		result.Frequencies = []int{}
+	}
+	for i, word := range leaf.Words {
+		if !keep(word, leaf.Frequency(i)) {
+			continue
+		}
+		result.Words = append(result.Words, word)
+		if leaf.Frequencies != nil {
+			result.Frequencies = append(result.Frequencies, leaf.Frequencies[i])
+		}
+	}
+	return result
+}
+
+// byFrequency sorts the words of a leaf by frequency, the most frequent words first
+type byFrequency anagram.Leaf
+
+func (l byFrequency) Len() int           { return len(l.Words) }
+func (l byFrequency) Less(i, j int) bool { return l.Frequencies[i] > l.Frequencies[j] }
+func (l byFrequency) Swap(i, j int) {
+	l.Words[i], l.Words[j] = l.Words[j], l.Words[i]
+	l.Frequencies[i], l.Frequencies[j] = l.Frequencies[j], l.Frequencies[i]
+}
+
+// multiByFrequency sorts multi-anagrams by the frequency of their least frequent word, the most frequent first
+type multiByFrequency struct {
+	results  []MultiAnagram
+	minFreqs []int
+}
+
+func (m multiByFrequency) Len() int           { return len(m.results) }
+func (m multiByFrequency) Less(i, j int) bool { return m.minFreqs[i] > m.minFreqs[j] }
+func (m multiByFrequency) Swap(i, j int) {
+	m.results[i], m.results[j] = m.results[j], m.results[i]
+	m.minFreqs[i], m.minFreqs[j] = m.minFreqs[j], m.minFreqs[i]
+}
@@ -0,0 +1,165 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/xwrd/anagram"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	eng, err := New(strings.NewReader("abc\nbca\n\ncab\nabcdef\nfedcba\n"), Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"abc", "bca", "cab", "abcdef", "fedcba"}, eng.Words(), "Wrong words")
+
+	progress := []int{}
+	eng = NewFromWords(eng.Words(), Options{Progress: func(p int) { progress = append(progress, p) }})
+	eng.Build()
+	assert.Equal(t, 100, progress[len(progress)-1], "Wrong final progress")
+}
+
+func TestEngineAnagrams(t *testing.T) {
+	eng := NewFromWords([]string{"abc", "bca", "cab", "abcdef", "fedcba"}, Options{})
+
+	ana := eng.Anagrams("abc", Query{})
+	assert.Equal(t, []Anagram{{Words: []string{"abc", "bca", "cab"}}}, ana, "Wrong anagrams")
+
+	ana = eng.Anagrams("bca", Query{MaxUnknown: 3})
+	assert.Equal(t,
+		[]Anagram{
+			{Words: []string{"abc", "bca", "cab"}, Added: []rune{}},
+			{Words: []string{"abcdef", "fedcba"}, Added: []rune("def")},
+		},
+		ana, "Wrong anagrams with unknowns")
+
+	pattern, err := Pattern("c*")
+	assert.Nil(t, err)
+
+	ana = eng.PartialAnagrams("abcdef", Query{Filter: pattern})
+	assert.Equal(t, []Anagram{{Words: []string{"cab"}}}, ana, "Wrong filtered partial anagrams")
+
+	multi := eng.MultiAnagrams("abcabc", Query{})
+	assert.Equal(t,
+		[]MultiAnagram{{Words: [][]string{{"abc", "bca", "cab"}, {"abc", "bca", "cab"}}}},
+		multi, "Wrong multi-anagrams")
+
+	multi = eng.MultiAnagrams("abcabc", Query{Filter: pattern})
+	assert.Equal(t,
+		[]MultiAnagram{{Words: [][]string{{"cab"}, {"abc", "bca", "cab"}}}},
+		multi, "Wrong filtered multi-anagrams")
+}
+
+func TestEngineMatch(t *testing.T) {
+	eng := NewFromWords([]string{"abc", "Bca", "cab", "abcdef", "fedcba"}, Options{})
+
+	tt := []struct {
+		title    string
+		pattern  string
+		expected []string
+	}{
+		{
+			title:    "periods",
+			pattern:  "b..",
+			expected: []string{"Bca"},
+		},
+		{
+			title:    "asterisk",
+			pattern:  "*a",
+			expected: []string{"Bca", "fedcba"},
+		},
+		{
+			title:    "no match",
+			pattern:  "x*",
+			expected: []string{},
+		},
+	}
+
+	for _, test := range tt {
+		pattern, err := Pattern(test.pattern)
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, eng.Match(pattern, Query{}), "Wrong matches in %s", test.title)
+	}
+}
+
+func TestEngineFrequencies(t *testing.T) {
+	freqs := map[string]int{"bca": 10, "cab": 5, "abcdef": 20, "ab": 1}
+	eng := NewFromWords([]string{"abc", "bca", "cab", "ab", "abcdef", "fedcba"}, Options{Frequencies: freqs})
+
+	ana := eng.Anagrams("abc", Query{})
+	assert.Equal(t, []Anagram{{Words: []string{"abc", "bca", "cab"}, Frequencies: []int{0, 10, 5}}}, ana, "Wrong anagrams")
+
+	ana = eng.Anagrams("abc", Query{SortByFreq: true})
+	assert.Equal(t, []string{"bca", "cab", "abc"}, ana[0].Words, "Wrong sorted anagrams")
+	assert.Equal(t, []int{10, 5, 0}, ana[0].Frequencies, "Wrong sorted frequencies")
+	assert.Equal(t, anagram.Leaf{Words: []string{"abc", "bca", "cab"}, Frequencies: []int{0, 10, 5}}, eng.Tree().Anagrams("abc"), "Tree leaf modified by sorting")
+
+	ana = eng.Anagrams("abc", Query{MinFreq: 6})
+	assert.Equal(t, []string{"bca"}, ana[0].Words, "Wrong anagrams with min. frequency")
+
+	ana = eng.PartialAnagrams("abcdef", Query{SortByFreq: true})
+	words := [][]string{}
+	for _, a := range ana {
+		words = append(words, a.Words)
+	}
+	assert.Equal(t, [][]string{{"abcdef", "fedcba"}, {"bca", "cab", "abc"}, {"ab"}}, words, "Wrong sorted partial anagrams")
+
+	multi := eng.MultiAnagrams("abcabc", Query{MinFreq: 6})
+	assert.Equal(t, []MultiAnagram{{Words: [][]string{{"bca"}, {"bca"}}}}, multi, "Wrong multi-anagrams with min. frequency")
+
+	eng = NewFromWords([]string{"ab", "cd", "ac", "bd"}, Options{Frequencies: map[string]int{"ab": 1, "cd": 8, "ac": 5, "bd": 3}})
+	multi = eng.MultiAnagrams("abcd", Query{SortByFreq: true})
+	assert.Equal(t, []MultiAnagram{{Words: [][]string{{"ac"}, {"bd"}}}, {Words: [][]string{{"cd"}, {"ab"}}}}, multi, "Wrong sorted multi-anagrams")
+
+	eng = NewFromWords([]string{"abc", "bca", "cab", "ab", "abcdef", "fedcba"}, Options{Frequencies: freqs})
+	pattern, err := Pattern("*")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"abcdef", "bca", "cab", "ab", "abc", "fedcba"}, eng.Match(pattern, Query{SortByFreq: true}), "Wrong sorted matches")
+	assert.Equal(t, []string{"bca", "abcdef"}, eng.Match(pattern, Query{MinFreq: 10}), "Wrong matches with min. frequency")
+}
+
+func TestEngineCase(t *testing.T) {
+	words := []string{"Polish", "polish", "Lisa", "sail", "nail"}
+
+	eng := NewFromWords(words, Options{CaseSensitive: true})
+	assert.Equal(t, []Anagram{{Words: []string{"polish"}}}, eng.Anagrams("hsilop", Query{}))
+	assert.Equal(t, []Anagram{{Words: []string{"Lisa"}}}, eng.Anagrams("asiL", Query{}))
+	assert.Equal(t,
+		[]Anagram{
+			{Words: []string{"sail"}, Added: []rune{}},
+			{Words: []string{"nail"}, Added: []rune{}},
+			{Words: []string{"Lisa"}, Added: []rune("L")},
+		},
+		eng.PartialAnagrams("sailn", Query{MinLength: 4, MaxUnknown: 1}))
+
+	eng = NewFromWords(words, Options{ProperNouns: ProperNounsExclude})
+	assert.Equal(t, []string{"polish", "sail", "nail"}, eng.Words())
+	assert.Equal(t, []Anagram{{Words: []string{"polish"}}}, eng.Anagrams("Polish", Query{}))
+
+	eng = NewFromWords(words, Options{ProperNouns: ProperNounsOnly})
+	assert.Equal(t, []string{"Polish", "Lisa"}, eng.Words())
+
+	pattern, err := CaseSensitivePattern("P*")
+	assert.Nil(t, err)
+	eng = NewFromWords(words, Options{})
+	assert.Equal(t, []string{"Polish"}, eng.Match(pattern, Query{}))
+	pattern, err = Pattern("P*")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Polish", "polish"}, eng.Match(pattern, Query{}))
+}
+
+func TestEngineIgnored(t *testing.T) {
+	words := []string{"ice cream", "mother-in-law", "o'clock", "cinema"}
+
+	eng := NewFromWords(words, Options{})
+	assert.Equal(t, []Anagram{{Words: []string{"ice cream"}}}, eng.Anagrams("ceramic e", Query{}))
+	assert.Equal(t, []Anagram{{Words: []string{"mother-in-law"}}}, eng.Anagrams("woman hitler", Query{}))
+	assert.Equal(t,
+		[]Anagram{{Words: []string{"ice cream"}, Added: []rune("e")}},
+		eng.Anagrams("ceramic", Query{MaxUnknown: 1}))
+	assert.Equal(t, []Anagram{}, eng.Anagrams("clocko", Query{}))
+
+	eng = NewFromWords(words, Options{Ignored: " '"})
+	assert.Equal(t, []Anagram{{Words: []string{"o'clock"}}}, eng.Anagrams("clocko", Query{}))
+	assert.Equal(t, []Anagram{}, eng.Anagrams("woman hitler", Query{}), "Hyphens should not be ignored")
+}
@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+var (
+	// ErrBadPattern is an error for invalid patterns
+	ErrBadPattern = errors.New("invalid pattern")
+
+	periods   = regexp.MustCompile("\\.+")
+	asterisks = regexp.MustCompile("\\*+")
+)
+
+// Pattern creates a case-insensitive regular expression from a word pattern.
+//
+// '.' (period) stands for one arbitrary letter,
+// '*' (asterisk) stands for 0 or more arbitrary letters.
+func Pattern(word string) (*regexp.Regexp, error) {
+	return createPattern(word, false)
+}
+
+// CaseSensitivePattern creates a case-sensitive regular expression from a word pattern. See Pattern
+func CaseSensitivePattern(word string) (*regexp.Regexp, error) {
+	return createPattern(word, true)
+}
+
+func createPattern(word string, caseSensitive bool) (*regexp.Regexp, error) {
+	pattern := periods.ReplaceAllStringFunc(
+		word,
+		func(m string) string {
+			return fmt.Sprintf("\\p{L}{%d}", utf8.RuneCountInString(m))
+		},
+	)
+	pattern = asterisks.ReplaceAllStringFunc(
+		pattern,
+		func(m string) string {
+			return "\\p{L}*"
+		},
+	)
+	pattern = fmt.Sprintf("^%s$", pattern)
+	if !caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	exp, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w '%s': %s", ErrBadPattern, word, err)
+	}
+	return exp, nil
+}
@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PhraseBuilder builds a phrase anagram word by word, keeping track of the leftover letters
+type PhraseBuilder struct {
+	engine    *Engine
+	remaining []int
+	words     []string
+	redo      []string
+}
+
+// NewPhraseBuilder creates a PhraseBuilder for the letters of a phrase
+func (e *Engine) NewPhraseBuilder(phrase string) *PhraseBuilder {
+	return &PhraseBuilder{
+		engine:    e,
+		remaining: e.letterHistogram(phrase),
+		words:     []string{},
+		redo:      []string{},
+	}
+}
+
+// letterHistogram returns the histogram of letter counts of a word, without characters outside the tree's letters.
+// Such characters, like apostrophes and digits, could never be used up by picked words.
+func (e *Engine) letterHistogram(word string) []int {
+	tree := e.Tree()
+	hist := tree.Histogram(word)
+	hist[tree.LettersMap['?']] = 0
+	return hist
+}
+
+// Candidates finds partial anagrams of the remaining letters
+func (b *PhraseBuilder) Candidates(q Query) []Anagram {
+	leaves := b.engine.Tree().PartialAnagramsHist(b.remaining, q.MinLength)
+	results := []Anagram{}
+	for _, leaf := range leaves {
+		leaf = b.engine.rank(filter(leaf, q.Filter), q)
+		if len(leaf.Words) == 0 {
+			continue
+		}
+		results = append(results, Anagram{Words: leaf.Words, Frequencies: leaf.Frequencies})
+	}
+	b.engine.sortAnagrams(results, q)
+	return results
+}
+
+// Pick adds a word to the phrase and removes its letters from the remaining letters.
+// Returns an error if the word does not fit into the remaining letters.
+func (b *PhraseBuilder) Pick(word string) error {
+	if err := b.pick(word); err != nil {
+		return err
+	}
+	b.redo = b.redo[:0]
+	return nil
+}
+
+func (b *PhraseBuilder) pick(word string) error {
+	hist := b.engine.letterHistogram(word)
+	total := 0
+	for i, cnt := range hist {
+		if cnt > b.remaining[i] {
+			return fmt.Errorf("word '%s' does not fit into the remaining letters '%s'", word, b.Remaining())
+		}
+		total += cnt
+	}
+	if total == 0 {
+		return fmt.Errorf("word '%s' contains no letters", word)
+	}
+	for i, cnt := range hist {
+		b.remaining[i] -= cnt
+	}
+	b.words = append(b.words, word)
+	return nil
+}
+
+// Undo removes the last picked word, and returns its letters to the remaining letters.
+// Returns false if there is nothing to undo.
+func (b *PhraseBuilder) Undo() bool {
+	if len(b.words) == 0 {
+		return false
+	}
+	word := b.words[len(b.words)-1]
+	b.words = b.words[:len(b.words)-1]
+
+	hist := b.engine.letterHistogram(word)
+	for i, cnt := range hist {
+		b.remaining[i] += cnt
+	}
+	b.redo = append(b.redo, word)
+	return true
+}
+
+// Redo picks the last undone word again.
+// Returns false if there is nothing to redo.
+func (b *PhraseBuilder) Redo() bool {
+	if len(b.redo) == 0 {
+		return false
+	}
+	word := b.redo[len(b.redo)-1]
+	if err := b.pick(word); err != nil {
+		return false
+	}
+	b.redo = b.redo[:len(b.redo)-1]
+	return true
+}
+
+// Words returns the picked words
+func (b *PhraseBuilder) Words() []string {
+	return b.words
+}
+
+// Phrase returns the picked words as a phrase
+func (b *PhraseBuilder) Phrase() string {
+	return strings.Join(b.words, " ")
+}
+
+// Remaining returns the remaining letters, sorted alphabetically
+func (b *PhraseBuilder) Remaining() string {
+	letters := b.engine.Tree().Letters
+	runes := []rune{}
+	for i, cnt := range b.remaining {
+		for j := 0; j < cnt; j++ {
+			runes = append(runes, letters[i])
+		}
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return string(runes)
+}
+
+// Done returns whether there are no remaining letters
+func (b *PhraseBuilder) Done() bool {
+	for _, cnt := range b.remaining {
+		if cnt > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhraseBuilder(t *testing.T) {
+	eng := NewFromWords([]string{"dormitory", "dirty", "room", "moor", "or", "dry", "tim"}, Options{})
+
+	b := eng.NewPhraseBuilder("Dirty Room")
+	assert.Equal(t, "dimoorrty", b.Remaining(), "Wrong remaining letters")
+	assert.False(t, b.Done())
+
+	cand := b.Candidates(Query{MinLength: 4})
+	assert.Equal(t, []Anagram{{Words: []string{"room", "moor"}}, {Words: []string{"dirty"}}, {Words: []string{"dormitory"}}}, cand, "Wrong candidates")
+
+	assert.NotNil(t, b.Pick("rooms"), "Expected error for word not fitting")
+	assert.NotNil(t, b.Pick("--"), "Expected error for word without letters")
+	assert.Nil(t, b.Pick("room"))
+	assert.Equal(t, "dirty", b.Remaining(), "Wrong remaining letters")
+	assert.Equal(t, []Anagram{{Words: []string{"dry"}}, {Words: []string{"dirty"}}}, b.Candidates(Query{MinLength: 3}), "Wrong candidates")
+
+	assert.True(t, b.Undo())
+	assert.False(t, b.Undo())
+	assert.Equal(t, "dimoorrty", b.Remaining(), "Wrong remaining letters after undo")
+
+	assert.True(t, b.Redo())
+	assert.False(t, b.Redo())
+	assert.Equal(t, []string{"room"}, b.Words(), "Wrong words after redo")
+
+	assert.Nil(t, b.Pick("Dirty"))
+	assert.True(t, b.Done())
+	assert.Equal(t, "room Dirty", b.Phrase(), "Wrong phrase")
+	assert.Equal(t, "", b.Remaining(), "Wrong remaining letters")
+
+	assert.True(t, b.Undo())
+	assert.Nil(t, b.Pick("dry"))
+	assert.False(t, b.Redo(), "Expected redo to be cleared by pick")
+}
+
+func TestPhraseBuilderUnknown(t *testing.T) {
+	eng := NewFromWords([]string{"it's", "late", "tale", "sit"}, Options{})
+
+	b := eng.NewPhraseBuilder("it's late 2")
+	assert.Equal(t, "aeilstt", b.Remaining(), "Remaining letters should not contain unknown characters")
+
+	assert.Nil(t, b.Pick("it's"))
+	assert.Equal(t, "aelt", b.Remaining(), "Wrong remaining letters")
+	assert.Equal(t, []Anagram{{Words: []string{"late", "tale"}}}, b.Candidates(Query{}), "Wrong candidates")
+
+	assert.Nil(t, b.Pick("late"))
+	assert.True(t, b.Done(), "Phrase with unknown characters should be finished")
+}
@@ -0,0 +1,430 @@
+// Package hunspell reads Hunspell dictionaries, and expands their stems to full word forms using the affix rules.
+package hunspell
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrFormat is an error for invalid affix or dictionary files
+var ErrFormat = errors.New("invalid hunspell file")
+
+// Flag types of affix files
+const (
+	flagChar = "char"
+	flagLong = "long"
+	flagNum  = "num"
+)
+
+// Rule is a single prefix or suffix rule
+type Rule struct {
+	Strip string
+	Add   string
+	Flags []string
+	cond  *regexp.Regexp
+}
+
+// Affix is a prefix or suffix class, with its rules
+type Affix struct {
+	Flag   string
+	Prefix bool
+	Cross  bool
+	Rules  []Rule
+}
+
+// AffixFile holds the affix rules and options of a Hunspell affix file (.aff)
+type AffixFile struct {
+	// Encoding is the declared character encoding (SET), like UTF-8 or ISO8859-1
+	Encoding string
+	Prefixes map[string]*Affix
+	Suffixes map[string]*Affix
+
+	flagType       string
+	aliases        [][]string
+	needAffix      string
+	forbidden      string
+	onlyInCompound string
+}
+
+// Encoding returns the character encoding declared in an affix file, or an empty string if none
+func Encoding(aff []byte) string {
+	for _, line := range strings.Split(string(aff), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 1 && fields[0] == "SET" {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// ParseAffixFile parses the content of an affix file. The content must be UTF-8 encoded.
+func ParseAffixFile(aff []byte) (*AffixFile, error) {
+	a := &AffixFile{
+		Prefixes: map[string]*Affix{},
+		Suffixes: map[string]*Affix{},
+		flagType: flagChar,
+	}
+
+	lines := strings.Split(string(aff), "\n")
+	for i := 0; i < len(lines); i++ {
+		fields := strings.Fields(lines[i])
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch fields[0] {
+		case "SET":
+			a.Encoding = value(fields)
+		case "FLAG":
+			switch strings.ToLower(value(fields)) {
+			case "long":
+				a.flagType = flagLong
+			case "num":
+				a.flagType = flagNum
+			default:
+				a.flagType = flagChar
+			}
+		case "NEEDAFFIX", "PSEUDOROOT":
+			a.needAffix = value(fields)
+		case "FORBIDDENWORD":
+			a.forbidden = value(fields)
+		case "ONLYINCOMPOUND":
+			a.onlyInCompound = value(fields)
+		case "AF":
+			n, err := a.parseAliases(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			i += n
+		case "PFX", "SFX":
+			n, err := a.parseAffix(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			i += n
+		}
+	}
+	return a, nil
+}
+
+// parseAliases parses a flag alias header (AF) at line index i, and the alias lines.
+// Returns the number of alias lines.
+func (a *AffixFile) parseAliases(lines []string, i int) (int, error) {
+	count, err := strconv.Atoi(value(strings.Fields(lines[i])))
+	if err != nil {
+		return 0, fmt.Errorf("%w: line %d: invalid alias count '%s'", ErrFormat, i+1, lines[i])
+	}
+	aliases := make([][]string, 0, count)
+	for j := 1; j <= count; j++ {
+		if i+j >= len(lines) {
+			return 0, fmt.Errorf("%w: line %d: expected %d flag aliases", ErrFormat, i+1, count)
+		}
+		fields := strings.Fields(lines[i+j])
+		if len(fields) < 2 || fields[0] != "AF" {
+			return 0, fmt.Errorf("%w: line %d: invalid flag alias '%s'", ErrFormat, i+j+1, lines[i+j])
+		}
+		aliases = append(aliases, a.parseFlags(fields[1]))
+	}
+	a.aliases = aliases
+	return count, nil
+}
+
+// parseAffix parses an affix class header at line index i, and its rules.
+// Returns the number of rule lines.
+func (a *AffixFile) parseAffix(lines []string, i int) (int, error) {
+	fields := strings.Fields(lines[i])
+	if len(fields) < 4 {
+		return 0, fmt.Errorf("%w: line %d: invalid affix header '%s'", ErrFormat, i+1, lines[i])
+	}
+	count, err := strconv.Atoi(fields[3])
+	if err != nil {
+		return 0, fmt.Errorf("%w: line %d: invalid rule count '%s'", ErrFormat, i+1, fields[3])
+	}
+
+	affix := &Affix{
+		Flag:   fields[1],
+		Prefix: fields[0] == "PFX",
+		Cross:  fields[2] == "Y",
+	}
+	if affix.Prefix {
+		a.Prefixes[affix.Flag] = affix
+	} else {
+		a.Suffixes[affix.Flag] = affix
+	}
+
+	for j := 1; j <= count; j++ {
+		if i+j >= len(lines) {
+			return 0, fmt.Errorf("%w: line %d: expected %d rules for affix %s", ErrFormat, i+1, count, affix.Flag)
+		}
+		rule := strings.Fields(lines[i+j])
+		if len(rule) < 4 || rule[0] != fields[0] || rule[1] != affix.Flag {
+			return 0, fmt.Errorf("%w: line %d: invalid affix rule '%s'", ErrFormat, i+j+1, lines[i+j])
+		}
+		r, err := a.parseRule(rule, affix.Prefix)
+		if err != nil {
+			return 0, fmt.Errorf("%w: line %d: %s", ErrFormat, i+j+1, err)
+		}
+		affix.Rules = append(affix.Rules, r)
+	}
+	return count, nil
+}
+
+func (a *AffixFile) parseRule(fields []string, prefix bool) (Rule, error) {
+	r := Rule{Strip: fields[2], Add: fields[3]}
+	if r.Strip == "0" {
+		r.Strip = ""
+	}
+	if idx := strings.Index(r.Add, "/"); idx >= 0 {
+		r.Flags = a.parseFlags(r.Add[idx+1:])
+		r.Add = r.Add[:idx]
+	}
+	if r.Add == "0" {
+		r.Add = ""
+	}
+
+	cond := "."
+	if len(fields) > 4 {
+		cond = fields[4]
+	}
+	pattern := condition(cond)
+	if prefix {
+		pattern = "^(?:" + pattern + ")"
+	} else {
+		pattern = "(?:" + pattern + ")$"
+	}
+	var err error
+	if r.cond, err = regexp.Compile(pattern); err != nil {
+		return r, fmt.Errorf("invalid condition '%s'", cond)
+	}
+	return r, nil
+}
+
+// parseFlags splits a flag string according to the flag type, and resolves flag aliases
+func (a *AffixFile) parseFlags(flags string) []string {
+	if len(a.aliases) > 0 && isNumber(flags) {
+		idx, _ := strconv.Atoi(flags)
+		if idx > 0 && idx <= len(a.aliases) {
+			return a.aliases[idx-1]
+		}
+	}
+	result := []string{}
+	switch a.flagType {
+	case flagNum:
+		for _, f := range strings.Split(flags, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				result = append(result, f)
+			}
+		}
+	case flagLong:
+		runes := []rune(flags)
+		for i := 0; i+1 < len(runes); i += 2 {
+			result = append(result, string(runes[i:i+2]))
+		}
+	default:
+		for _, r := range flags {
+			result = append(result, string(r))
+		}
+	}
+	return result
+}
+
+// Expand reads a dictionary file (.dic), and expands all stems to their word forms.
+// The content must be UTF-8 encoded. Returns the deduplicated words, and the number of stems.
+func (a *AffixFile) Expand(dic []byte) ([]string, int, error) {
+	words := []string{}
+	seen := map[string]bool{}
+	stems := 0
+
+	for i, line := range strings.Split(string(dic), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || (i == 0 && isNumber(line)) {
+			continue
+		}
+		stem, flags := a.splitEntry(line)
+		if stem == "" {
+			return nil, 0, fmt.Errorf("%w: line %d: empty word", ErrFormat, i+1)
+		}
+		stems++
+		for _, word := range a.ExpandWord(stem, flags) {
+			if !seen[word] {
+				seen[word] = true
+				words = append(words, word)
+			}
+		}
+	}
+	return words, stems, nil
+}
+
+// splitEntry splits a dictionary line into the stem and its flags. Morphological fields are ignored.
+func (a *AffixFile) splitEntry(line string) (string, []string) {
+	entry := strings.Fields(line)[0]
+	for i := 0; i < len(entry); i++ {
+		if entry[i] == '\\' {
+			i++
+			continue
+		}
+		if entry[i] == '/' && i > 0 {
+			return strings.ReplaceAll(entry[:i], "\\/", "/"), a.parseFlags(entry[i+1:])
+		}
+	}
+	return strings.ReplaceAll(entry, "\\/", "/"), nil
+}
+
+// ExpandWord expands a stem with the given affix flags to all word forms
+func (a *AffixFile) ExpandWord(stem string, flags []string) []string {
+	if a.has(flags, a.forbidden) {
+		return nil
+	}
+	words := []string{}
+	if !a.has(flags, a.needAffix) && !a.has(flags, a.onlyInCompound) {
+		words = append(words, stem)
+	}
+
+	type form struct {
+		word  string
+		cross bool
+		flags []string
+	}
+	suffixed := []form{}
+	for _, flag := range flags {
+		affix, ok := a.Suffixes[flag]
+		if !ok {
+			continue
+		}
+		for _, word := range affix.apply(stem) {
+			suffixed = append(suffixed, form{word: word.word, cross: affix.Cross, flags: word.flags})
+		}
+	}
+	// Continuation flags of suffix rules allow a second suffix
+	for _, s := range suffixed {
+		for _, flag := range s.flags {
+			if affix, ok := a.Suffixes[flag]; ok {
+				for _, word := range affix.apply(s.word) {
+					suffixed = append(suffixed, form{word: word.word, cross: s.cross && affix.Cross})
+				}
+			}
+		}
+	}
+	for _, s := range suffixed {
+		if !a.has(s.flags, a.needAffix) {
+			words = append(words, s.word)
+		}
+	}
+
+	for _, flag := range flags {
+		affix, ok := a.Prefixes[flag]
+		if !ok {
+			continue
+		}
+		for _, word := range affix.apply(stem) {
+			if !a.has(word.flags, a.needAffix) {
+				words = append(words, word.word)
+			}
+		}
+		if !affix.Cross {
+			continue
+		}
+		// Prefix conditions are checked on the stem, not on the suffixed word
+		for _, r := range affix.Rules {
+			if !r.cond.MatchString(stem) {
+				continue
+			}
+			for _, s := range suffixed {
+				if !s.cross {
+					continue
+				}
+				if word, ok := affix.applyRule(&r, s.word); ok {
+					words = append(words, word)
+				}
+			}
+		}
+	}
+	return words
+}
+
+type derived struct {
+	word  string
+	flags []string
+}
+
+// apply applies all matching rules of the affix to a word
+func (affix *Affix) apply(word string) []derived {
+	result := []derived{}
+	for _, r := range affix.Rules {
+		if !r.cond.MatchString(word) {
+			continue
+		}
+		if w, ok := affix.applyRule(&r, word); ok {
+			result = append(result, derived{word: w, flags: r.Flags})
+		}
+	}
+	return result
+}
+
+// applyRule strips and adds the affix of a rule, without checking the rule's condition
+func (affix *Affix) applyRule(r *Rule, word string) (string, bool) {
+	if affix.Prefix {
+		if !strings.HasPrefix(word, r.Strip) {
+			return "", false
+		}
+		return r.Add + strings.TrimPrefix(word, r.Strip), true
+	}
+	if !strings.HasSuffix(word, r.Strip) {
+		return "", false
+	}
+	return strings.TrimSuffix(word, r.Strip) + r.Add, true
+}
+
+func (a *AffixFile) has(flags []string, flag string) bool {
+	if flag == "" {
+		return false
+	}
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// condition converts an affix condition to a regular expression.
+// Conditions consist of literal characters, '.' for any character, and character classes like [abc] or [^abc].
+func condition(cond string) string {
+	sb := strings.Builder{}
+	inClass := false
+	for i, r := range cond {
+		switch {
+		case inClass && r == ']':
+			inClass = false
+			sb.WriteRune(r)
+		case inClass && r == '^' && i > 0 && cond[i-1] == '[':
+			sb.WriteRune(r)
+		case inClass && r == '-':
+			sb.WriteString(`\-`)
+		case inClass:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		case r == '[':
+			inClass = true
+			sb.WriteRune(r)
+		case r == '.':
+			sb.WriteRune(r)
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return sb.String()
+}
+
+func value(fields []string) string {
+	if len(fields) < 2 {
+		return ""
+	}
+	return fields[1]
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
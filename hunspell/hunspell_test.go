@@ -0,0 +1,98 @@
+package hunspell
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpand(t *testing.T) {
+	aff, err := os.ReadFile(filepath.Join("testdata", "test.aff"))
+	assert.Nil(t, err)
+	dic, err := os.ReadFile(filepath.Join("testdata", "test.dic"))
+	assert.Nil(t, err)
+
+	assert.Equal(t, "UTF-8", Encoding(aff))
+
+	affixes, err := ParseAffixFile(aff)
+	assert.Nil(t, err)
+
+	words, stems, err := affixes.Expand(dic)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, stems)
+	assert.Equal(t, []string{
+		"create", "created", "creating", "creates", "recreate", "recreated", "recreating", "recreates",
+		"try", "tried", "tries",
+		"kind", "kindness", "kindnesses", "unkind",
+		"box", "boxes",
+		"zeds",
+	}, words)
+}
+
+func TestExpandLongFlags(t *testing.T) {
+	aff := []byte(`FLAG long
+AF 2
+AF AaBb
+AF Bb
+
+SFX Aa Y 1
+SFX Aa 0 es .
+
+SFX Bb Y 2
+SFX Bb 0 n e
+SFX Bb 0 en [^e]
+`)
+	dic := []byte("Kind/1\nMöwe/2\nAmt/Aa\n")
+
+	affixes, err := ParseAffixFile(aff)
+	assert.Nil(t, err)
+
+	words, stems, err := affixes.Expand(dic)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, stems)
+	assert.Equal(t, []string{"Kind", "Kindes", "Kinden", "Möwe", "Möwen", "Amt", "Amtes"}, words)
+}
+
+func TestExpandCrossProduct(t *testing.T) {
+	aff := []byte(`PFX P Y 1
+PFX P 0 re o
+
+PFX Q Y 1
+PFX Q 0 un i
+
+SFX S Y 1
+SFX S o i o
+`)
+	dic := []byte("o/PQS\n")
+
+	affixes, err := ParseAffixFile(aff)
+	assert.Nil(t, err)
+
+	words, _, err := affixes.Expand(dic)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"o", "i", "reo", "rei"}, words, "Prefix conditions should be checked on the stem")
+}
+
+func TestParseErrors(t *testing.T) {
+	tt := []struct {
+		title string
+		aff   string
+	}{
+		{title: "bad count", aff: "SFX A Y x\n"},
+		{title: "missing rules", aff: "SFX A Y 2\nSFX A 0 s .\n"},
+		{title: "wrong flag", aff: "SFX A Y 1\nSFX B 0 s .\n"},
+		{title: "bad condition", aff: "SFX A Y 1\nSFX A 0 s [a\n"},
+		{title: "bad aliases", aff: "AF 2\nAF A\n"},
+	}
+	for _, test := range tt {
+		_, err := ParseAffixFile([]byte(test.aff))
+		assert.ErrorIs(t, err, ErrFormat, "Expected error in %s", test.title)
+	}
+}
+
+func TestCondition(t *testing.T) {
+	assert.Equal(t, `[^aeiou]y`, condition("[^aeiou]y"))
+	assert.Equal(t, `.\+[a\-z]`, condition(".+[a-z]"))
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -12,16 +13,19 @@ import (
 const version = "0.1.3"
 
 func main() {
-	util.EnsureDirs()
+	root := util.DefaultRootDir()
+	util.EnsureDirs(root)
 
-	config, err := core.LoadConfig()
+	config, err := core.LoadConfig(root)
 	if err != nil {
-		fmt.Printf("ERROR: %s\n", err.Error())
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "ERROR: %s\n", err.Error())
+		os.Exit(cli.ExitError)
 	}
 
-	if err := cli.RootCommand(&config, version).Execute(); err != nil {
-		fmt.Printf("ERROR: %s\n", err.Error())
-		os.Exit(1)
+	if err := cli.RootCommand(&config, version, cli.StdStreams(), root).Execute(); err != nil {
+		if !errors.Is(err, cli.ErrNoResults) {
+			fmt.Fprintf(os.Stderr, "ERROR: %s\n", err.Error())
+		}
+		os.Exit(cli.ExitCode(err))
 	}
 }
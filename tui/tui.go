@@ -0,0 +1,281 @@
+// Package tui provides a full-screen terminal user interface with live anagram and pattern search.
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mlange-42/xwrd/engine"
+)
+
+type searchMode int
+
+const (
+	modeNormal searchMode = iota
+	modePartial
+	modeMulti
+	modeMatch
+)
+
+var modeNames = []string{"normal", "partial", "multi", "match"}
+
+type field int
+
+const (
+	fieldInput field = iota
+	fieldFilter
+	fieldUnknown
+	numFields
+)
+
+var fieldLabels = []string{"Word:    ", "Filter:  ", "Unknown: "}
+
+const (
+	headerRows = 5
+	help       = "Tab: next field  F2: next mode  Up/Down/PgUp/PgDn: scroll  Esc: quit"
+)
+
+var (
+	styleDefault = tcell.StyleDefault
+	styleTitle   = tcell.StyleDefault.Bold(true)
+	styleActive  = tcell.StyleDefault.Reverse(true)
+	styleError   = tcell.StyleDefault.Foreground(tcell.ColorRed)
+	styleHelp    = tcell.StyleDefault.Dim(true)
+)
+
+// App is the terminal user interface
+type App struct {
+	screen  tcell.Screen
+	engine  *engine.Engine
+	mode    searchMode
+	focus   field
+	fields  [numFields][]rune
+	base    engine.Query
+	query   engine.Query
+	results []string
+	err     string
+	scroll  int
+}
+
+// New creates a new App for an initialized screen.
+// The base query holds settings that can't be changed in the UI, like frequency filtering and sorting.
+func New(screen tcell.Screen, eng *engine.Engine, base engine.Query) *App {
+	return &App{
+		screen:  screen,
+		engine:  eng,
+		base:    base,
+		results: []string{},
+	}
+}
+
+// Run draws the user interface and processes events until the user quits
+func (a *App) Run() error {
+	a.draw()
+	for {
+		ev := a.screen.PollEvent()
+		if ev == nil {
+			return nil
+		}
+		if !a.handle(ev) {
+			return nil
+		}
+		a.draw()
+	}
+}
+
+// handle processes an event. Returns false if the app should quit
+func (a *App) handle(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *tcell.EventResize:
+		a.screen.Sync()
+	case *tcell.EventKey:
+		switch ev.Key() {
+		case tcell.KeyEscape, tcell.KeyCtrlC:
+			return false
+		case tcell.KeyTab:
+			a.focus = (a.focus + 1) % numFields
+		case tcell.KeyBacktab:
+			a.focus = (a.focus + numFields - 1) % numFields
+		case tcell.KeyF2:
+			a.mode = (a.mode + 1) % searchMode(len(modeNames))
+			a.update()
+		case tcell.KeyUp:
+			a.scrollBy(-1)
+		case tcell.KeyDown:
+			a.scrollBy(1)
+		case tcell.KeyPgUp:
+			a.scrollBy(-a.pageSize())
+		case tcell.KeyPgDn:
+			a.scrollBy(a.pageSize())
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			text := a.fields[a.focus]
+			if len(text) > 0 {
+				a.fields[a.focus] = text[:len(text)-1]
+				a.update()
+			}
+		case tcell.KeyRune:
+			a.fields[a.focus] = append(a.fields[a.focus], ev.Rune())
+			a.update()
+		}
+	}
+	return true
+}
+
+// update parses the settings and re-runs the query
+func (a *App) update() {
+	a.results = a.results[:0]
+	a.scroll = 0
+	a.err = ""
+
+	query, err := parseQuery(a.base, string(a.fields[fieldFilter]), string(a.fields[fieldUnknown]))
+	if err != nil {
+		a.err = err.Error()
+		return
+	}
+	a.query = query
+
+	word := strings.TrimSpace(string(a.fields[fieldInput]))
+	if word == "" {
+		return
+	}
+
+	switch a.mode {
+	case modeNormal:
+		a.addAnagrams(a.engine.Anagrams(word, a.query))
+	case modePartial:
+		a.addAnagrams(a.engine.PartialAnagrams(word, a.query))
+	case modeMulti:
+		for _, ana := range a.engine.MultiAnagrams(word, a.query) {
+			blocks := make([]string, len(ana.Words))
+			for i, block := range ana.Words {
+				blocks[i] = strings.Join(block, "  ")
+			}
+			a.results = append(a.results, strings.Join(blocks, "  |  "))
+		}
+	case modeMatch:
+		pattern, err := engine.Pattern(word)
+		if err != nil {
+			a.err = err.Error()
+			return
+		}
+		a.results = append(a.results, a.engine.Match(pattern, a.query)...)
+	}
+}
+
+func (a *App) addAnagrams(anagrams []engine.Anagram) {
+	for _, ana := range anagrams {
+		line := strings.Join(ana.Words, "  ")
+		if len(ana.Added) > 0 {
+			line += fmt.Sprintf("  (+%s)", string(ana.Added))
+		}
+		a.results = append(a.results, line)
+	}
+}
+
+func parseQuery(base engine.Query, filter string, unknown string) (engine.Query, error) {
+	query := base
+	if filter != "" {
+		pattern, err := engine.Pattern(filter)
+		if err != nil {
+			return query, err
+		}
+		query.Filter = pattern
+	}
+
+	unknown = strings.TrimSpace(unknown)
+	if unknown == "" {
+		return query, nil
+	}
+	parts := strings.Split(unknown, ",")
+	if len(parts) > 2 {
+		return query, fmt.Errorf("unknown expects one or two numbers")
+	}
+	values := make([]uint, len(parts))
+	for i, p := range parts {
+		val, err := strconv.ParseUint(strings.TrimSpace(p), 10, 32)
+		if err != nil {
+			return query, fmt.Errorf("invalid unknown '%s'", unknown)
+		}
+		values[i] = uint(val)
+	}
+	query.MinUnknown, query.MaxUnknown = values[0], values[len(values)-1]
+	if query.MinUnknown > query.MaxUnknown {
+		return query, fmt.Errorf("unknown: 2nd number must not be smaller than 1st number")
+	}
+	return query, nil
+}
+
+func (a *App) pageSize() int {
+	_, height := a.screen.Size()
+	size := height - headerRows - 1
+	if size < 1 {
+		return 1
+	}
+	return size
+}
+
+func (a *App) scrollBy(rows int) {
+	a.scroll += rows
+	if last := len(a.results) - a.pageSize(); a.scroll > last {
+		a.scroll = last
+	}
+	if a.scroll < 0 {
+		a.scroll = 0
+	}
+}
+
+func (a *App) draw() {
+	a.screen.Clear()
+	width, height := a.screen.Size()
+
+	x := drawText(a.screen, 0, 0, width, styleTitle, "xwrd  ")
+	for m, name := range modeNames {
+		style := styleDefault
+		if searchMode(m) == a.mode {
+			style = styleActive
+		}
+		x = drawText(a.screen, x, 0, width, style, " "+name+" ")
+	}
+
+	for f := field(0); f < numFields; f++ {
+		x := drawText(a.screen, 0, int(f)+1, width, styleDefault, fieldLabels[f])
+		x = drawText(a.screen, x, int(f)+1, width, styleDefault, string(a.fields[f]))
+		if f == a.focus {
+			a.screen.ShowCursor(x, int(f)+1)
+		}
+	}
+
+	status := fmt.Sprintf("%d results", len(a.results))
+	if len(a.results) == 1 {
+		status = "1 result"
+	}
+	style := styleDefault
+	if a.err != "" {
+		status = a.err
+		style = styleError
+	}
+	x = drawText(a.screen, 0, headerRows-1, width, styleDefault, "-- ")
+	drawText(a.screen, x, headerRows-1, width, style, status)
+
+	page := a.pageSize()
+	for i := 0; i < page && a.scroll+i < len(a.results); i++ {
+		drawText(a.screen, 2, headerRows+i, width, styleDefault, a.results[a.scroll+i])
+	}
+
+	drawText(a.screen, 0, height-1, width, styleHelp, help)
+	a.screen.Show()
+}
+
+// drawText draws text starting at x, clipped at maxX. Returns the x position after the text.
+func drawText(screen tcell.Screen, x, y, maxX int, style tcell.Style, text string) int {
+	for _, r := range text {
+		if x >= maxX {
+			break
+		}
+		screen.SetContent(x, y, r, nil, style)
+		x++
+	}
+	return x
+}
@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mlange-42/xwrd/engine"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestApp(t *testing.T) (*App, tcell.SimulationScreen) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(60, 12)
+
+	eng := engine.NewFromWords([]string{"abc", "bca", "cab", "abcdef", "fedcba", "ab", "ba"}, engine.Options{})
+	return New(screen, eng, engine.Query{}), screen
+}
+
+// screenLines returns the content of the simulated screen as lines of text
+func screenLines(screen tcell.SimulationScreen) []string {
+	cells, width, height := screen.GetContents()
+	lines := make([]string, height)
+	for y := 0; y < height; y++ {
+		line := []rune{}
+		for x := 0; x < width; x++ {
+			r := cells[y*width+x].Runes
+			if len(r) == 0 {
+				line = append(line, ' ')
+			} else {
+				line = append(line, r[0])
+			}
+		}
+		lines[y] = strings.TrimRight(string(line), " ")
+	}
+	return lines
+}
+
+func typeText(app *App, text string) {
+	for _, r := range text {
+		app.handle(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	app.draw()
+}
+
+func pressKey(app *App, key tcell.Key) bool {
+	res := app.handle(tcell.NewEventKey(key, 0, tcell.ModNone))
+	app.draw()
+	return res
+}
+
+func TestLiveResults(t *testing.T) {
+	app, screen := newTestApp(t)
+	defer screen.Fini()
+
+	typeText(app, "ab")
+	lines := screenLines(screen)
+	assert.Equal(t, "Word:    ab", lines[1], "Wrong input line")
+	assert.Equal(t, "-- 1 result", lines[4], "Wrong status line")
+	assert.Equal(t, "  ab  ba", lines[5], "Wrong results")
+
+	typeText(app, "c")
+	lines = screenLines(screen)
+	assert.Equal(t, "  abc  bca  cab", lines[5], "Wrong results after typing")
+
+	pressKey(app, tcell.KeyBackspace2)
+	lines = screenLines(screen)
+	assert.Equal(t, "  ab  ba", lines[5], "Wrong results after backspace")
+}
+
+func TestModesAndSettings(t *testing.T) {
+	app, screen := newTestApp(t)
+	defer screen.Fini()
+
+	typeText(app, "abcdef")
+	pressKey(app, tcell.KeyF2)
+	lines := screenLines(screen)
+	assert.Equal(t, "xwrd   normal  partial  multi  match", lines[0], "Wrong mode line")
+	assert.Equal(t, "-- 3 results", lines[4], "Wrong partial results")
+
+	pressKey(app, tcell.KeyTab)
+	typeText(app, "c*")
+	lines = screenLines(screen)
+	assert.Equal(t, "Filter:  c*", lines[2], "Wrong filter line")
+	assert.Equal(t, []string{"  cab"}, lines[5:6], "Wrong filtered results")
+
+	pressKey(app, tcell.KeyTab)
+	typeText(app, "2,1")
+	lines = screenLines(screen)
+	assert.True(t, strings.HasPrefix(lines[4], "-- unknown"), "Expected error for invalid unknown")
+
+	pressKey(app, tcell.KeyBackspace2)
+	pressKey(app, tcell.KeyBackspace2)
+	pressKey(app, tcell.KeyBackspace2)
+	pressKey(app, tcell.KeyF2)
+	pressKey(app, tcell.KeyF2)
+	pressKey(app, tcell.KeyBacktab)
+	pressKey(app, tcell.KeyBacktab)
+	pressKey(app, tcell.KeyBackspace2)
+	pressKey(app, tcell.KeyBackspace2)
+	pressKey(app, tcell.KeyBackspace2)
+	typeText(app, "*a")
+	lines = screenLines(screen)
+	assert.Equal(t, "Word:    abc*a", lines[1], "Wrong pattern")
+	assert.Equal(t, "-- 0 results", lines[4], "Wrong match results")
+}
+
+func TestScrollAndQuit(t *testing.T) {
+	app, screen := newTestApp(t)
+	defer screen.Fini()
+	app.mode = modeMatch
+
+	typeText(app, "*")
+	assert.Equal(t, 7, len(app.results), "Wrong number of results")
+
+	lines := screenLines(screen)
+	assert.Equal(t, "  abc", lines[5], "Wrong first result")
+
+	pressKey(app, tcell.KeyDown)
+	lines = screenLines(screen)
+	assert.Equal(t, "  bca", lines[5], "Wrong first result after scrolling")
+
+	pressKey(app, tcell.KeyPgDn)
+	assert.Equal(t, 1, app.scroll, "Scrolled too far")
+
+	assert.False(t, pressKey(app, tcell.KeyEscape), "Expected quit on Esc")
+}
+
+func TestRun(t *testing.T) {
+	app, screen := newTestApp(t)
+	defer screen.Fini()
+
+	for _, r := range "abc" {
+		screen.InjectKey(tcell.KeyRune, r, tcell.ModNone)
+	}
+	screen.InjectKey(tcell.KeyEscape, 0, tcell.ModNone)
+
+	assert.Nil(t, app.Run())
+	assert.Equal(t, "  abc  bca  cab", screenLines(screen)[5], "Wrong results")
+}
@@ -0,0 +1,167 @@
+package util
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ErrArchive is an error for archives that can't be unpacked, or where the word list can't be selected
+var ErrArchive = errors.New("invalid archive")
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zipMagic  = []byte("PK\x03\x04")
+	tarMagic  = []byte("ustar")
+)
+
+const tarMagicOffset = 257
+
+// Unpack extracts a word list from gzip, zip or tar content, or from any combination like .tar.gz.
+// Plain content is returned unchanged.
+//
+// Argument file selects the word list inside zip and tar archives, by path or base name.
+// It can be empty for archives that contain exactly one file.
+func Unpack(content []byte, file string) ([]byte, error) {
+	for {
+		var err error
+		switch {
+		case bytes.HasPrefix(content, gzipMagic):
+			content, err = gunzip(content)
+		case bytes.HasPrefix(content, zipMagic):
+			content, err = unzip(content, file)
+		case len(content) > tarMagicOffset+len(tarMagic) &&
+			bytes.Equal(content[tarMagicOffset:tarMagicOffset+len(tarMagic)], tarMagic):
+			content, err = untar(content, file)
+		default:
+			return content, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Compress compresses content with gzip
+func Compress(content []byte) ([]byte, error) {
+	buf := bytes.Buffer{}
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(content); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gunzip(content []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrArchive, err)
+	}
+	defer reader.Close()
+
+	result, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrArchive, err)
+	}
+	return result, nil
+}
+
+func unzip(content []byte, file string) ([]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrArchive, err)
+	}
+
+	names := []string{}
+	for _, f := range reader.File {
+		if !f.FileInfo().IsDir() {
+			names = append(names, f.Name)
+		}
+	}
+	name, err := selectFile(names, file)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := reader.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrArchive, err)
+	}
+	defer f.Close()
+
+	result, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrArchive, err)
+	}
+	return result, nil
+}
+
+func untar(content []byte, file string) ([]byte, error) {
+	files := map[string][]byte{}
+	names := []string{}
+
+	reader := tar.NewReader(bytes.NewReader(content))
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrArchive, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrArchive, err)
+		}
+		files[header.Name] = data
+		names = append(names, header.Name)
+	}
+
+	name, err := selectFile(names, file)
+	if err != nil {
+		return nil, err
+	}
+	return files[name], nil
+}
+
+// selectFile selects a file from an archive by path or base name
+func selectFile(names []string, file string) (string, error) {
+	if file == "" {
+		if len(names) == 1 {
+			return names[0], nil
+		}
+		if len(names) == 0 {
+			return "", fmt.Errorf("%w: archive contains no files", ErrArchive)
+		}
+		return "", fmt.Errorf("%w: archive contains multiple files, select one of: %s", ErrArchive, strings.Join(names, ", "))
+	}
+
+	matches := []string{}
+	for _, name := range names {
+		if name == file {
+			return name, nil
+		}
+		if path.Base(name) == file {
+			matches = append(matches, name)
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("%w: file '%s' not found in archive", ErrArchive, file)
+	}
+	return "", fmt.Errorf("%w: file name '%s' is ambiguous, select one of: %s", ErrArchive, file, strings.Join(matches, ", "))
+}
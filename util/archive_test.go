@@ -0,0 +1,77 @@
+package util
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makeZip(t *testing.T, files map[string]string) []byte {
+	buf := bytes.Buffer{}
+	writer := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := writer.Create(name)
+		assert.Nil(t, err)
+		_, err = w.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, writer.Close())
+	return buf.Bytes()
+}
+
+func makeTar(t *testing.T, files map[string]string) []byte {
+	buf := bytes.Buffer{}
+	writer := tar.NewWriter(&buf)
+	for name, content := range files {
+		err := writer.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		assert.Nil(t, err)
+		_, err = writer.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, writer.Close())
+	return buf.Bytes()
+}
+
+func makeGzip(t *testing.T, content []byte) []byte {
+	result, err := Compress(content)
+	assert.Nil(t, err)
+	return result
+}
+
+func TestUnpack(t *testing.T) {
+	words := "alpha\nbeta\n"
+	multi := map[string]string{"README": "readme", "lists/words.txt": words, "other/words.txt": "x"}
+
+	tt := []struct {
+		title    string
+		content  []byte
+		file     string
+		expected string
+		err      error
+	}{
+		{title: "plain", content: []byte(words), expected: words},
+		{title: "gzip", content: makeGzip(t, []byte(words)), expected: words},
+		{title: "zip", content: makeZip(t, map[string]string{"words.txt": words}), expected: words},
+		{title: "tar", content: makeTar(t, map[string]string{"words.txt": words}), expected: words},
+		{title: "tar.gz", content: makeGzip(t, makeTar(t, map[string]string{"a/words.txt": words})), expected: words},
+		{title: "zip by path", content: makeZip(t, multi), file: "lists/words.txt", expected: words},
+		{title: "tar by name", content: makeTar(t, multi), file: "README", expected: "readme"},
+		{title: "zip multiple", content: makeZip(t, multi), err: ErrArchive},
+		{title: "tar ambiguous", content: makeTar(t, multi), file: "words.txt", err: ErrArchive},
+		{title: "tar missing", content: makeTar(t, multi), file: "missing.txt", err: ErrArchive},
+		{title: "broken gzip", content: []byte{0x1f, 0x8b, 0x00}, err: ErrArchive},
+	}
+
+	for _, test := range tt {
+		result, err := Unpack(test.content, test.file)
+		if test.err != nil {
+			assert.ErrorIs(t, err, test.err, "Expected error in %s", test.title)
+			continue
+		}
+		assert.Nil(t, err, "Unexpected error in %s", test.title)
+		assert.Equal(t, test.expected, string(result), "Wrong content in %s", test.title)
+	}
+}
@@ -1,18 +1,29 @@
 package util
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	// ErrUsage is an error for invalid arguments or flags
+	ErrUsage = errors.New("invalid usage")
+)
+
 // WrappedArgs are PositionalArgs that print usage on error
 func WrappedArgs(fn cobra.PositionalArgs) cobra.PositionalArgs {
 	return func(cmd *cobra.Command, args []string) error {
 		err := fn(cmd, args)
 		if err != nil {
-			return fmt.Errorf("%s\nUsage: %s", err, cmd.UseLine())
+			return UsageError(cmd, err)
 		}
 		return nil
 	}
 }
+
+// UsageError wraps an error into an ErrUsage, and appends the command's usage line
+func UsageError(cmd *cobra.Command, err error) error {
+	return fmt.Errorf("%w: %s\nUsage: %s", ErrUsage, err, cmd.UseLine())
+}
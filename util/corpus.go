@@ -0,0 +1,161 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// frequencySeparator separates words and their frequency in word lists
+const frequencySeparator = "\t"
+
+// ParseEntry splits a line of a word list into the word and its frequency.
+// The frequency is an optional, tab-separated second column. It is 0 if absent or invalid.
+func ParseEntry(line string) (string, int) {
+	word, freq, ok := strings.Cut(line, frequencySeparator)
+	if !ok {
+		return line, 0
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(freq))
+	if err != nil {
+		return word, 0
+	}
+	return word, count
+}
+
+// WordCount is a word with its number of occurrences
+type WordCount struct {
+	Word  string
+	Count int
+}
+
+// String formats the word count as a word list line, with tab-separated frequency
+func (w WordCount) String() string {
+	return fmt.Sprintf("%s%s%d", w.Word, frequencySeparator, w.Count)
+}
+
+// CorpusOptions configure the word list created by a Corpus
+type CorpusOptions struct {
+	// MinCount is the minimum number of occurrences
+	MinCount int
+	// MinLength is the minimum word length, in letters
+	MinLength int
+	// KeepCase counts words with different capitalization separately.
+	// Otherwise, they are merged, and the most frequent spelling is used.
+	KeepCase bool
+}
+
+// Corpus counts words in texts
+type Corpus struct {
+	counts map[string]int
+}
+
+// NewCorpus creates an empty Corpus
+func NewCorpus() *Corpus {
+	return &Corpus{counts: map[string]int{}}
+}
+
+// Add tokenizes a text, and counts its words
+func (c *Corpus) Add(text string) {
+	Tokenize(text, func(word string) {
+		c.counts[word]++
+	})
+}
+
+// Words returns the words with their counts, most frequent first, and in alphabetical order for equal counts
+func (c *Corpus) Words(opts CorpusOptions) []WordCount {
+	counts := c.counts
+	if !opts.KeepCase {
+		counts = mergeCase(counts)
+	}
+
+	words := []WordCount{}
+	for word, count := range counts {
+		if count < opts.MinCount || utf8.RuneCountInString(word) < opts.MinLength {
+			continue
+		}
+		words = append(words, WordCount{Word: word, Count: count})
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if words[i].Count != words[j].Count {
+			return words[i].Count > words[j].Count
+		}
+		return words[i].Word < words[j].Word
+	})
+	return words
+}
+
+// mergeCase merges spellings that only differ in case, and uses the most frequent spelling
+func mergeCase(counts map[string]int) map[string]int {
+	type spelling struct {
+		word  string
+		count int
+		total int
+	}
+	merged := map[string]*spelling{}
+	for word, count := range counts {
+		key := strings.ToLower(word)
+		s, ok := merged[key]
+		if !ok {
+			merged[key] = &spelling{word: word, count: count, total: count}
+			continue
+		}
+		s.total += count
+		if count > s.count || (count == s.count && word > s.word) {
+			s.word, s.count = word, count
+		}
+	}
+	result := make(map[string]int, len(merged))
+	for _, s := range merged {
+		result[s.word] = s.total
+	}
+	return result
+}
+
+// Tokenize splits text into words, and calls fn for each word.
+// Words are sequences of letters and combining marks,
+// and may contain single apostrophes or hyphens between a letter or mark and a letter.
+// Words are normalized to Unicode NFC.
+func Tokenize(text string, fn func(word string)) {
+	text = norm.NFC.String(text)
+	start := -1
+	var prev rune
+	for i, r := range text {
+		switch {
+		case isWordChar(r):
+			if start < 0 {
+				start = i
+			}
+		case start >= 0 && isJoiner(r) && isWordChar(prev) && nextIsLetter(text[i+utf8.RuneLen(r):]):
+		default:
+			if start >= 0 {
+				fn(text[start:i])
+				start = -1
+			}
+		}
+		prev = r
+	}
+	if start >= 0 {
+		fn(text[start:])
+	}
+}
+
+// isWordChar checks for letters and combining marks, incl. spacing and enclosing marks
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.Is(unicode.M, r)
+}
+
+// isJoiner checks for characters that can join letters within words
+func isJoiner(r rune) bool {
+	return r == '\'' || r == '’' || r == '-'
+}
+
+func nextIsLetter(text string) bool {
+	r, _ := utf8.DecodeRuneInString(text)
+	return unicode.IsLetter(r)
+}
@@ -0,0 +1,60 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	tt := []struct {
+		text     string
+		expected []string
+	}{
+		{text: "The quick, brown fox!", expected: []string{"The", "quick", "brown", "fox"}},
+		{text: "don't well-known -- 'quoted' end-", expected: []string{"don't", "well-known", "quoted", "end"}},
+		{text: "Über straße 42abc", expected: []string{"Über", "straße", "abc"}},
+		{text: "Möwe", expected: []string{"Möwe"}},
+		{text: "", expected: []string{}},
+	}
+	for _, test := range tt {
+		words := []string{}
+		Tokenize(test.text, func(word string) { words = append(words, word) })
+		assert.Equal(t, test.expected, words, "Wrong tokens for '%s'", test.text)
+	}
+}
+
+func TestCorpusWords(t *testing.T) {
+	corpus := NewCorpus()
+	corpus.Add("The cat and the dog, and the end.")
+	corpus.Add("A cat is a Cat.")
+
+	assert.Equal(t, []WordCount{
+		{Word: "cat", Count: 3},
+		{Word: "the", Count: 3},
+		{Word: "a", Count: 2},
+		{Word: "and", Count: 2},
+	}, corpus.Words(CorpusOptions{MinCount: 2}))
+
+	assert.Equal(t, []WordCount{
+		{Word: "and", Count: 2},
+		{Word: "cat", Count: 2},
+		{Word: "the", Count: 2},
+	}, corpus.Words(CorpusOptions{MinCount: 2, MinLength: 3, KeepCase: true}))
+}
+
+func TestParseEntry(t *testing.T) {
+	word, freq := ParseEntry("word\t42")
+	assert.Equal(t, "word", word)
+	assert.Equal(t, 42, freq)
+
+	word, freq = ParseEntry("word")
+	assert.Equal(t, "word", word)
+	assert.Equal(t, 0, freq)
+
+	word, freq = ParseEntry("word\tx")
+	assert.Equal(t, "word", word)
+	assert.Equal(t, 0, freq)
+
+	assert.Equal(t, "word\t42", WordCount{Word: "word", Count: 42}.String())
+}
@@ -1,20 +1,44 @@
 package util
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
-	"io"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrNoDictionary is an error for dictionaries that are not installed
+	ErrNoDictionary = errors.New("dictionary not installed")
+	// ErrUnknownDictionary is an error for dictionaries that are not available for download
+	ErrUnknownDictionary = errors.New("dictionary not available")
+	// ErrDownload is an error for failed dictionary downloads
+	ErrDownload = errors.New("download failed")
+	// ErrNotText is an error for dictionary files that are not text
+	ErrNotText = errors.New("not a text file")
+	// ErrDictionaryExists is an error for dictionaries that are already installed
+	ErrDictionaryExists = errors.New("dictionary already exists")
+	// ErrStarterDictionary is an error for changes to embedded starter dictionaries that are not possible
+	ErrStarterDictionary = errors.New("embedded starter dictionary")
 )
 
 // Dict represents a known word list
 type Dict struct {
-	Name     string
-	Language string
-	URL      string
+	Name        string `yaml:"name"`
+	Language    string `yaml:"language"`
+	URL         string `yaml:"url,omitempty"`
+	License     string `yaml:"license,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Checksum    string `yaml:"checksum,omitempty"`
+	Encoding    string `yaml:"encoding,omitempty"`
+	Format      string `yaml:"format,omitempty"`
+	File        string `yaml:"file,omitempty"`
+	// Filter selects the words that are loaded. Not part of the registry
+	Filter LoadFilter `yaml:"-"`
 }
 
 // NewDict creates a new dictionary
@@ -37,53 +61,161 @@ func (d *Dict) FullName() string {
 	return fmt.Sprintf("%s/%s", d.Language, d.Name)
 }
 
-// Dictionaries list dictionaries available for download
-var Dictionaries = map[string][]Dict{
-	"de": {
-		{
-			Name:     "enz",
-			Language: "de",
-			URL:      "https://raw.githubusercontent.com/enz/german-wordlist/master/words",
-		},
-	},
-	"en": {
-		{
-			Name:     "yawl",
-			Language: "en",
-			URL:      "https://raw.githubusercontent.com/elasticdog/yawl/master/yawl-0.3.2.03/word.list",
-		},
-	},
-}
-
-// LoadDictionary reads a file into a slice of words
-func LoadDictionary(dict Dict) ([]string, error) {
-	if !HasDictionary(dict) {
-		return nil, fmt.Errorf("no dictionary '%s/%s'. Download with: xwrd dict install %[1]s/%[2]s", dict.Language, dict.Name)
-	}
-	fileContent, err := ioutil.ReadFile(DictPath(dict))
+// LoadDictionary reads a file into a slice of words. Compressed files are unpacked transparently.
+// Word lists can have a tab-separated frequency column, which is stripped.
+// Carriage returns, surrounding whitespace and empty lines are removed.
+// The dictionary's overlay of added and blocked words is applied, followed by the dictionary's load filter.
+func LoadDictionary(root string, dict Dict) ([]string, error) {
+	words, _, err := LoadDictionaryFrequencies(root, dict)
+	return words, err
+}
+
+// LoadDictionaryFrequencies reads a file into a slice of words, like LoadDictionary.
+// Additionally, returns the word frequencies from the frequency column, if any.
+func LoadDictionaryFrequencies(root string, dict Dict) ([]string, Frequencies, error) {
+	fileContent, err := ReadDictionary(root, dict)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	words, freqs := splitEntries(fileContent)
+
+	overlay, err := LoadOverlay(root, dict)
+	if err != nil {
+		return nil, nil, err
+	}
+	words, err = dict.Filter.Apply(overlay.Apply(words))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load %s: %w", dict.FullName(), err)
 	}
-	words := strings.Split(string(fileContent), "\n")
-	return words, nil
+	return words, freqs, nil
 }
 
-// HasDictionary checks if a dict exists
-func HasDictionary(dict Dict) bool {
-	path := DictPath(dict)
-	return FileExists(path)
+// splitWords splits the content of a word list into words, and strips the frequency column, if any
+func splitWords(content []byte) []string {
+	words, _ := splitEntries(content)
+	return words
 }
 
-// AllDictionaries lists all installed dictionaries
-func AllDictionaries() (map[string]Dict, error) {
-	basePath := DictDir()
+// splitEntries splits the content of a word list into words and their frequencies.
+// Carriage returns and surrounding whitespace are stripped, and empty lines are skipped.
+// Words without a frequency are not contained in the frequencies.
+func splitEntries(content []byte) ([]string, Frequencies) {
+	lines := strings.Split(string(content), "\n")
+	words := make([]string, 0, len(lines))
+	freqs := Frequencies{}
+	for _, line := range lines {
+		word, freq := ParseEntry(line)
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		words = append(words, word)
+		if freq > 0 {
+			freqs[word] = freq
+		}
+	}
+	return words, freqs
+}
 
-	languages, err := os.ReadDir(basePath)
+// ReadDictionary reads the unpacked content of an installed dictionary, without applying its overlay.
+// Content that is not UTF-8 encoded is converted.
+// Starter dictionaries are read from the embedded files, unless there is an installed copy.
+func ReadDictionary(root string, dict Dict) ([]byte, error) {
+	path, ok := InstalledDictPath(root, dict)
+	if !ok && IsStarter(dict) {
+		return readStarter(dict)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s/%s'. Download with: xwrd dict install %[2]s/%[3]s", ErrNoDictionary, dict.Language, dict.Name)
+	}
+	return readText(path)
+}
+
+// readText reads a file, unpacks it and converts it to UTF-8
+func readText(path string) ([]byte, error) {
+	content, err := ioutil.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
+	if content, err = Unpack(content, ""); err != nil {
+		return nil, err
+	}
+	return Decode(content, "")
+}
+
+// Sources maps words to the names of the dictionaries they are contained in
+type Sources map[string][]string
+
+// Label returns the names of the dictionaries containing a word, comma-separated
+func (s Sources) Label(word string) string {
+	return strings.Join(s[word], ",")
+}
+
+// Frequencies maps words to their frequency from the frequency column of word lists.
+// Higher frequencies denote more familiar words.
+type Frequencies map[string]int
+
+// Merge adds the frequencies of other. For words contained in both, the highest frequency is kept
+func (f Frequencies) Merge(other Frequencies) {
+	for word, freq := range other {
+		if freq > f[word] {
+			f[word] = freq
+		}
+	}
+}
+
+// LoadDictionaries loads and merges multiple dictionaries. Words contained in multiple dictionaries are included only once.
+// Returns the merged words, the source dictionaries of each word, and the word frequencies.
+// For words with frequencies in multiple dictionaries, the highest frequency is used.
+func LoadDictionaries(root string, dicts []Dict) ([]string, Sources, Frequencies, error) {
+	words := []string{}
+	sources := Sources{}
+	frequencies := Frequencies{}
+	for _, dict := range dicts {
+		dictWords, dictFreqs, err := LoadDictionaryFrequencies(root, dict)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		frequencies.Merge(dictFreqs)
+		name := dict.FullName()
+		for _, word := range dictWords {
+			if word == "" {
+				continue
+			}
+			src, ok := sources[word]
+			if !ok {
+				words = append(words, word)
+			} else if src[len(src)-1] == name {
+				continue
+			}
+			sources[word] = append(src, name)
+		}
+	}
+	return words, sources, frequencies, nil
+}
+
+// HasDictionary checks if a dict exists. Starter dictionaries always exist
+func HasDictionary(root string, dict Dict) bool {
+	_, ok := InstalledDictPath(root, dict)
+	return ok || IsStarter(dict)
+}
+
+// AllDictionaries lists all installed dictionaries, and the starter dictionaries
+func AllDictionaries(root string) (map[string]Dict, error) {
+	basePath := DictDir(root)
 
 	results := map[string]Dict{}
+	for _, d := range StarterDictionaries() {
+		results[d.FullName()] = NewDict(d.FullName())
+	}
+
+	languages, err := os.ReadDir(basePath)
+	if os.IsNotExist(err) {
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
 
 	for _, lang := range languages {
 		if !lang.IsDir() {
@@ -100,33 +232,240 @@ func AllDictionaries() (map[string]Dict, error) {
 			if dict.IsDir() {
 				continue
 			}
-			d := NewDict(lang.Name() + "/" + dict.Name())
+			var name string
+			switch {
+			case strings.HasSuffix(dict.Name(), dictExtension):
+				name = strings.TrimSuffix(dict.Name(), dictExtension)
+			case strings.HasSuffix(dict.Name(), compressedExtension):
+				name = strings.TrimSuffix(dict.Name(), compressedExtension)
+			default:
+				continue
+			}
+			d := NewDict(lang.Name() + "/" + name)
 			results[d.FullName()] = d
 		}
 	}
 	return results, nil
 }
 
-// DownloadDictionary downloads a dictionary
-func DownloadDictionary(dict Dict) error {
-	path := DictPath(dict)
+// InstallDictionary installs a dictionary from its URL. The URL can be an http(s) URL, a file URL or a local path.
+// The content is verified against the dictionary's checksum, if any.
+// Gzip, zip and tar archives are unpacked, using the dictionary's File to select the word list inside the archive.
+// The word list is converted from the dictionary's Encoding, or from the detected encoding if empty, and normalized (see Normalize).
+// The dictionary's metadata is written (see Metadata).
+// Returns the number of words in the dictionary.
+func InstallDictionary(root string, dict Dict, opts DownloadOptions) (int, error) {
+	content, err := downloadDictionary(root, &dict, opts)
+	if err != nil {
+		return 0, err
+	}
+	if err = writeDictionary(root, dict, content, opts.Compress); err != nil {
+		return 0, err
+	}
+	return CountWords(content), nil
+}
 
-	resp, err := http.Get(dict.URL)
+// UpdateDictionary downloads an installed dictionary again, and replaces it if the content changed.
+// The dictionary keeps its storage format, plain or compressed.
+// Changes are detected by the checksum in the dictionary's metadata, or by comparing to the installed content if there is no metadata.
+// Returns the number of words in the dictionary, and whether it was updated.
+func UpdateDictionary(root string, dict Dict, opts DownloadOptions) (int, bool, error) {
+	path, ok := InstalledDictPath(root, dict)
+	if !ok {
+		return 0, false, fmt.Errorf("%w: '%s'", ErrNoDictionary, dict.FullName())
+	}
+	oldChecksum, err := installedChecksum(root, dict, path)
 	if err != nil {
-		return err
+		return 0, false, err
 	}
-	defer resp.Body.Close()
 
-	out, err := os.Create(path)
+	content, err := downloadDictionary(root, &dict, opts)
+	if err != nil {
+		return 0, false, err
+	}
+	if Checksum(content) == oldChecksum {
+		return CountWords(content), false, nil
+	}
+
+	if err = writeDictionary(root, dict, content, path == CompressedDictPath(root, dict)); err != nil {
+		return 0, false, err
+	}
+	return CountWords(content), true, nil
+}
+
+// installedChecksum returns the checksum of an installed dictionary from its metadata, or calculated from its content if there is no metadata
+func installedChecksum(root string, dict Dict, path string) (string, error) {
+	meta, ok, err := LoadMetadata(root, dict)
+	if err != nil {
+		return "", err
+	}
+	if ok && meta.Checksum != "" {
+		return meta.Checksum, nil
+	}
+	content, err := os.ReadFile(path)
 	if err != nil {
+		return "", err
+	}
+	if content, err = Unpack(content, ""); err != nil {
+		return "", err
+	}
+	return Checksum(content), nil
+}
+
+// RemoveDictionary removes an installed dictionary, together with its overlay and metadata.
+// Starter dictionaries can't be removed.
+func RemoveDictionary(root string, dict Dict) error {
+	if !HasDictionary(root, dict) {
+		return fmt.Errorf("%w: '%s'", ErrNoDictionary, dict.FullName())
+	}
+	if IsStarter(dict) {
+		return fmt.Errorf("%w: '%s' can't be removed", ErrStarterDictionary, dict.FullName())
+	}
+	for _, path := range []string{DictPath(root, dict), CompressedDictPath(root, dict), OverlayPath(root, dict), MetadataPath(root, dict)} {
+		if !FileExists(path) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenameDictionary renames an installed dictionary, together with its overlay and metadata. The target may be in another language.
+// Starter dictionaries can't be renamed.
+func RenameDictionary(root string, dict Dict, target Dict) error {
+	if IsStarter(dict) {
+		return fmt.Errorf("%w: '%s' can't be renamed", ErrStarterDictionary, dict.FullName())
+	}
+	path, ok := InstalledDictPath(root, dict)
+	if !ok {
+		return fmt.Errorf("%w: '%s'", ErrNoDictionary, dict.FullName())
+	}
+	if HasDictionary(root, target) {
+		return fmt.Errorf("%w: '%s'", ErrDictionaryExists, target.FullName())
+	}
+	if err := CreateDir(LanguageDir(root, target.Language)); err != nil {
 		return err
 	}
-	defer out.Close()
+	targetPath := DictPath(root, target)
+	if path == CompressedDictPath(root, dict) {
+		targetPath = CompressedDictPath(root, target)
+	}
+	if err := os.Rename(path, targetPath); err != nil {
+		return err
+	}
+	for _, paths := range [][2]string{
+		{OverlayPath(root, dict), OverlayPath(root, target)},
+		{MetadataPath(root, dict), MetadataPath(root, target)},
+	} {
+		if !FileExists(paths[0]) {
+			continue
+		}
+		if err := os.Rename(paths[0], paths[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// downloadDictionary fetches, verifies, unpacks, decodes and normalizes a dictionary.
+// Sets the dictionary's encoding to the detected encoding, if not given.
+func downloadDictionary(root string, dict *Dict, opts DownloadOptions) ([]byte, error) {
+	dir := LanguageDir(root, dict.Language)
+	if err := CreateDir(dir); err != nil {
+		return nil, err
+	}
+
+	temp, sum, err := fetch(dict.URL, dir, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(temp)
+
+	if err = VerifyChecksum(sum, dict.Checksum); err != nil {
+		return nil, err
+	}
 
-	_, err = io.Copy(out, resp.Body)
+	content, err := os.ReadFile(temp)
 	if err != nil {
+		return nil, err
+	}
+	content, err = Unpack(content, dict.File)
+	if err != nil {
+		return nil, err
+	}
+	if dict.Encoding == "" || dict.Encoding == "auto" {
+		dict.Encoding = DetectEncoding(content)
+	}
+	if content, err = Decode(content, dict.Encoding); err != nil {
+		return nil, err
+	}
+	if err = CheckText(content); err != nil {
+		return nil, err
+	}
+	return Normalize(content), nil
+}
+
+// writeDictionary atomically writes a word list to the dictionary's file, and removes an existing file in the other storage format.
+// Updates the dictionary's metadata.
+func writeDictionary(root string, dict Dict, content []byte, compress bool) error {
+	uncompressed := content
+	path, other := DictPath(root, dict), CompressedDictPath(root, dict)
+	if compress {
+		var err error
+		if content, err = Compress(content); err != nil {
+			return err
+		}
+		path, other = other, path
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".write-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err = file.Write(content); err != nil {
+		file.Close()
 		return err
 	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(file.Name(), path); err != nil {
+		return err
+	}
+	if FileExists(other) {
+		if err = os.Remove(other); err != nil {
+			return err
+		}
+	}
+	return updateMetadata(root, dict, uncompressed)
+}
 
+// CheckText checks that content is UTF-8 encoded text, without control characters other than whitespace
+func CheckText(content []byte) error {
+	if bytes.IndexFunc(content, isBinary) >= 0 {
+		return fmt.Errorf("%w: content contains binary data", ErrNotText)
+	}
+	if !utf8.Valid(content) {
+		return fmt.Errorf("%w: content is not valid UTF-8", ErrNotText)
+	}
 	return nil
 }
+
+func isBinary(r rune) bool {
+	return r < 0x20 && r != '\t' && r != '\n' && r != '\r' && r != '\f' && r != '\v'
+}
+
+// CountWords counts the non-empty lines in a word list
+func CountWords(content []byte) int {
+	count := 0
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,131 @@
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstallDictionary(t *testing.T) {
+	dir := t.TempDir()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("alpha\nbeta\ngamma\n"))
+	}))
+	defer server.Close()
+
+	source := filepath.Join(dir, "words.txt")
+	err := os.WriteFile(source, []byte("one\n\ntwo\n"), 0644)
+	assert.Nil(t, err)
+
+	tt := []struct {
+		title string
+		dict  Dict
+		count int
+	}{
+		{title: "http", dict: Dict{Language: "en", Name: "http", URL: server.URL}, count: 3},
+		{title: "local path", dict: Dict{Language: "en", Name: "path", URL: source}, count: 2},
+		{title: "file URL", dict: Dict{Language: "de", Name: "file", URL: "file://" + filepath.ToSlash(source)}, count: 2},
+	}
+
+	for _, test := range tt {
+		count, err := InstallDictionary(dir, test.dict, DefaultDownloadOptions())
+		assert.Nil(t, err, "Unexpected error in %s", test.title)
+		assert.Equal(t, test.count, count, "Wrong word count in %s", test.title)
+		assert.True(t, HasDictionary(dir, test.dict), "Dictionary not installed in %s", test.title)
+	}
+}
+
+func TestCheckText(t *testing.T) {
+	assert.Nil(t, CheckText([]byte("abc\nÄÖÜ\n")))
+	assert.ErrorIs(t, CheckText([]byte("abc\x00")), ErrNotText)
+	assert.ErrorIs(t, CheckText([]byte("abc\xe4")), ErrNotText)
+}
+
+func TestInstallDictionaryCompressed(t *testing.T) {
+	dir := t.TempDir()
+
+	source := filepath.Join(dir, "words.tar.gz")
+	archive := makeGzip(t, makeTar(t, map[string]string{"README": "readme", "words.txt": "one\ntwo\n"}))
+	err := os.WriteFile(source, archive, 0644)
+	assert.Nil(t, err)
+
+	dict := Dict{Language: "en", Name: "packed", URL: source, File: "words.txt"}
+	opts := DefaultDownloadOptions()
+	opts.Compress = true
+
+	count, err := InstallDictionary(dir, dict, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	assert.True(t, FileExists(CompressedDictPath(dir, dict)))
+	assert.False(t, FileExists(DictPath(dir, dict)))
+
+	words, err := LoadDictionary(dir, dict)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"one", "two"}, words)
+
+	all, err := AllDictionaries(dir)
+	assert.Nil(t, err)
+	assert.Contains(t, all, "en/packed")
+
+	opts.Compress = false
+	_, err = InstallDictionary(dir, dict, opts)
+	assert.Nil(t, err)
+	assert.True(t, FileExists(DictPath(dir, dict)))
+	assert.False(t, FileExists(CompressedDictPath(dir, dict)))
+}
+
+func TestRemoveRenameDictionary(t *testing.T) {
+	dir := t.TempDir()
+
+	source := filepath.Join(dir, "words.txt")
+	assert.Nil(t, os.WriteFile(source, []byte("one\ntwo\n"), 0644))
+
+	opts := DefaultDownloadOptions()
+	opts.Compress = true
+	dict := Dict{Language: "en", Name: "words", URL: source}
+	_, err := InstallDictionary(dir, dict, opts)
+	assert.Nil(t, err)
+
+	target := Dict{Language: "de", Name: "moved"}
+	assert.Nil(t, RenameDictionary(dir, dict, target))
+	assert.False(t, HasDictionary(dir, dict))
+	assert.True(t, FileExists(CompressedDictPath(dir, target)))
+
+	assert.ErrorIs(t, RenameDictionary(dir, dict, target), ErrNoDictionary)
+	_, err = InstallDictionary(dir, dict, opts)
+	assert.Nil(t, err)
+	assert.ErrorIs(t, RenameDictionary(dir, dict, target), ErrDictionaryExists)
+
+	assert.Nil(t, RemoveDictionary(dir, target))
+	assert.False(t, HasDictionary(dir, target))
+	assert.ErrorIs(t, RemoveDictionary(dir, target), ErrNoDictionary)
+}
+
+func TestLoadDictionaries(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.Nil(t, CreateDir(LanguageDir(dir, "en")))
+	assert.Nil(t, os.WriteFile(DictPath(dir, NewDict("en/a")), []byte("one\ntwo\ntwo\n"), 0644))
+	assert.Nil(t, os.WriteFile(DictPath(dir, NewDict("en/b")), []byte("two\t5\nthree\t2\n"), 0644))
+	assert.Nil(t, os.WriteFile(DictPath(dir, NewDict("en/crlf")), []byte("one\r\n two \r\n\r\nfour\t3\r\n"), 0644))
+
+	words, sources, freqs, err := LoadDictionaries(dir, []Dict{NewDict("en/a"), NewDict("en/b")})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"one", "two", "three"}, words)
+	assert.Equal(t, "en/a,en/b", sources.Label("two"))
+	assert.Equal(t, "en/b", sources.Label("three"))
+	assert.Equal(t, Frequencies{"two": 5, "three": 2}, freqs)
+
+	words, freqs, err = LoadDictionaryFrequencies(dir, NewDict("en/crlf"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"one", "two", "four"}, words)
+	assert.Equal(t, Frequencies{"four": 3}, freqs)
+
+	_, _, _, err = LoadDictionaries(dir, []Dict{NewDict("en/a"), NewDict("en/missing")})
+	assert.ErrorIs(t, err, ErrNoDictionary)
+}
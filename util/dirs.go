@@ -9,11 +9,18 @@ const (
 	rootDirName = ".xwrd"
 	dictDirName = "dict"
 	configName  = "config.yml"
-	defaultDict = "german-700k.txt"
+	historyName = "history"
+
+	dictExtension       = ".lst"
+	compressedExtension = ".lst.gz"
+	overlayExtension    = ".overlay.yml"
+	metadataExtension   = ".meta.yml"
+	defaultDict         = "german-700k.txt"
 )
 
-// RootDir returns the root storage directory
-func RootDir() string {
+// DefaultRootDir returns the default root storage directory, in the user's home directory.
+// Functions working with stored files take the root storage directory as their first argument.
+func DefaultRootDir() string {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		panic(err)
@@ -22,42 +29,66 @@ func RootDir() string {
 }
 
 // DictDir returns the dictionary storage directory
-func DictDir() string {
-	return filepath.Join(RootDir(), dictDirName)
+func DictDir(root string) string {
+	return filepath.Join(root, dictDirName)
 }
 
 // LanguageDir returns the dictionary storage directory for a language
-func LanguageDir(lang string) string {
-	return filepath.Join(RootDir(), dictDirName, lang)
+func LanguageDir(root string, lang string) string {
+	return filepath.Join(root, dictDirName, lang)
 }
 
 // DictPath returns the path to a dictionary file
-func DictPath(dict Dict) string {
-	return filepath.Join(RootDir(), dictDirName, dict.Language, dict.Name+".lst")
+func DictPath(root string, dict Dict) string {
+	return filepath.Join(root, dictDirName, dict.Language, dict.Name+dictExtension)
+}
+
+// CompressedDictPath returns the path to a gzip-compressed dictionary file
+func CompressedDictPath(root string, dict Dict) string {
+	return filepath.Join(root, dictDirName, dict.Language, dict.Name+compressedExtension)
+}
+
+// InstalledDictPath returns the path to the file of an installed dictionary, plain or compressed.
+// Returns false if the dictionary is not installed.
+func InstalledDictPath(root string, dict Dict) (string, bool) {
+	for _, path := range []string{DictPath(root, dict), CompressedDictPath(root, dict)} {
+		if FileExists(path) {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// OverlayPath returns the path to the overlay file of a dictionary
+func OverlayPath(root string, dict Dict) string {
+	return filepath.Join(root, dictDirName, dict.Language, dict.Name+overlayExtension)
+}
+
+// MetadataPath returns the path to the metadata file of a dictionary
+func MetadataPath(root string, dict Dict) string {
+	return filepath.Join(root, dictDirName, dict.Language, dict.Name+metadataExtension)
 }
 
 // ConfigPath returns the path to the config file
-func ConfigPath() string {
-	return filepath.Join(RootDir(), configName)
+func ConfigPath(root string) string {
+	return filepath.Join(root, configName)
+}
+
+// HistoryPath returns the path to the history file of interactive modes
+func HistoryPath(root string) string {
+	return filepath.Join(root, historyName)
 }
 
 // EnsureDirs creates storage directories if not present
-func EnsureDirs() {
-	err := CreateDir(RootDir())
+func EnsureDirs(root string) {
+	err := CreateDir(root)
 	if err != nil {
 		panic(err)
 	}
-	err = CreateDir(DictDir())
+	err = CreateDir(DictDir(root))
 	if err != nil {
 		panic(err)
 	}
-	for lang := range Dictionaries {
-		path := filepath.Join(DictDir(), lang)
-		err = CreateDir(path)
-		if err != nil {
-			panic(err)
-		}
-	}
 }
 
 // CreateDir creates directories recursively
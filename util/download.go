@@ -0,0 +1,166 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrChecksum is an error for downloads that do not match the expected checksum
+	ErrChecksum = errors.New("checksum mismatch")
+)
+
+// DownloadOptions configure dictionary downloads
+type DownloadOptions struct {
+	// Timeout per attempt. No timeout if 0
+	Timeout time.Duration
+	// Retries is the number of retries after failed attempts
+	Retries int
+	// RetryDelay is the delay before the first retry. Doubles with every retry
+	RetryDelay time.Duration
+	// Progress is called with the number of bytes received and the total size, which is -1 if unknown. Optional.
+	Progress func(done, total int64)
+	// Compress stores the installed dictionary gzip-compressed
+	Compress bool
+}
+
+// DefaultDownloadOptions returns the default download options
+func DefaultDownloadOptions() DownloadOptions {
+	return DownloadOptions{
+		Timeout:    60 * time.Second,
+		Retries:    2,
+		RetryDelay: time.Second,
+	}
+}
+
+// fetch copies content from an http(s) URL, a file URL or a local path into a temporary file in dir.
+// Returns the path of the temporary file and the SHA-256 checksum of the content.
+func fetch(source string, dir string, opts DownloadOptions) (string, string, error) {
+	u, err := url.Parse(source)
+	if err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		var path, sum string
+		delay := opts.RetryDelay
+		for attempt := 0; attempt <= opts.Retries; attempt++ {
+			if attempt > 0 {
+				time.Sleep(delay)
+				delay *= 2
+			}
+			path, sum, err = fetchHTTP(source, dir, opts)
+			if err == nil || !retryable(err) {
+				break
+			}
+		}
+		return path, sum, err
+	}
+
+	path := source
+	if err == nil && u.Scheme == "file" {
+		path = filepath.FromSlash(u.Path)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer file.Close()
+
+	size := int64(-1)
+	if info, err := file.Stat(); err == nil {
+		size = info.Size()
+	}
+	return writeTemp(file, size, dir, opts.Progress)
+}
+
+type statusError struct {
+	status string
+	code   int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s: unexpected status %s", ErrDownload, e.status)
+}
+
+func (e *statusError) Unwrap() error {
+	return ErrDownload
+}
+
+func retryable(err error) bool {
+	var status *statusError
+	if errors.As(err, &status) {
+		return status.code >= 500 || status.code == http.StatusTooManyRequests
+	}
+	return errors.Is(err, ErrDownload)
+}
+
+func fetchHTTP(source string, dir string, opts DownloadOptions) (string, string, error) {
+	client := http.Client{Timeout: opts.Timeout}
+
+	resp, err := client.Get(source)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %s", ErrDownload, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", &statusError{status: resp.Status, code: resp.StatusCode}
+	}
+
+	path, sum, err := writeTemp(resp.Body, resp.ContentLength, dir, opts.Progress)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %s", ErrDownload, err)
+	}
+	return path, sum, nil
+}
+
+// writeTemp writes content to a temporary file, and calculates its SHA-256 checksum
+func writeTemp(reader io.Reader, size int64, dir string, progress func(done, total int64)) (string, string, error) {
+	file, err := os.CreateTemp(dir, ".download-*")
+	if err != nil {
+		return "", "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	writer := io.MultiWriter(file, hash)
+	if progress != nil {
+		writer = io.MultiWriter(writer, &progressWriter{total: size, progress: progress})
+	}
+
+	if _, err = io.Copy(writer, reader); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", "", err
+	}
+
+	return file.Name(), hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+type progressWriter struct {
+	done     int64
+	total    int64
+	progress func(done, total int64)
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.done += int64(len(p))
+	w.progress(w.done, w.total)
+	return len(p), nil
+}
+
+// VerifyChecksum compares a SHA-256 checksum to the expected checksum, which may be prefixed with "sha256:".
+// Succeeds if no checksum is expected.
+func VerifyChecksum(sum string, expected string) error {
+	expected = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(expected), "sha256:"))
+	if expected == "" || expected == sum {
+		return nil
+	}
+	return fmt.Errorf("%w: expected SHA-256 %s, got %s", ErrChecksum, expected, sum)
+}
@@ -0,0 +1,150 @@
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testContent  = "alpha\nbeta\ngamma\n"
+	testChecksum = "sha256:4fdbc441ea7b546100e086ac1e4fc5ae6749b7314311c99db05be450eca12996"
+)
+
+func testOptions() DownloadOptions {
+	return DownloadOptions{Timeout: time.Second, Retries: 2, RetryDelay: time.Millisecond}
+}
+
+func TestInstallDictionaryStatus(t *testing.T) {
+	dir := t.TempDir()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	dict := Dict{Language: "en", Name: "missing", URL: server.URL}
+	_, err := InstallDictionary(dir, dict, testOptions())
+	assert.ErrorIs(t, err, ErrDownload)
+	assert.Equal(t, 1, requests, "Client errors should not be retried")
+	assert.False(t, HasDictionary(dir, dict))
+	assertNoTempFiles(t, LanguageDir(dir, "en"))
+}
+
+func TestInstallDictionaryRetry(t *testing.T) {
+	dir := t.TempDir()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(testContent))
+	}))
+	defer server.Close()
+
+	dict := Dict{Language: "en", Name: "flaky", URL: server.URL}
+	count, err := InstallDictionary(dir, dict, testOptions())
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, 3, requests)
+
+	requests = 0
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	opts := testOptions()
+	opts.Retries = 1
+	dict = Dict{Language: "en", Name: "broken", URL: broken.URL}
+	_, err = InstallDictionary(dir, dict, opts)
+	assert.ErrorIs(t, err, ErrDownload)
+	assert.Equal(t, 2, requests)
+	assert.False(t, HasDictionary(dir, dict))
+}
+
+func TestInstallDictionaryChecksum(t *testing.T) {
+	dir := t.TempDir()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testContent))
+	}))
+	defer server.Close()
+
+	dict := Dict{Language: "en", Name: "good", URL: server.URL, Checksum: testChecksum}
+	_, err := InstallDictionary(dir, dict, testOptions())
+	assert.Nil(t, err)
+	assert.True(t, HasDictionary(dir, dict))
+
+	dict = Dict{Language: "en", Name: "bad", URL: server.URL, Checksum: "0123456789abcdef"}
+	_, err = InstallDictionary(dir, dict, testOptions())
+	assert.ErrorIs(t, err, ErrChecksum)
+	assert.False(t, HasDictionary(dir, dict))
+	assertNoTempFiles(t, LanguageDir(dir, "en"))
+}
+
+func TestInstallDictionaryTimeout(t *testing.T) {
+	dir := t.TempDir()
+
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	opts := testOptions()
+	opts.Timeout = 20 * time.Millisecond
+	opts.Retries = 0
+
+	dict := Dict{Language: "en", Name: "slow", URL: server.URL}
+	_, err := InstallDictionary(dir, dict, opts)
+	assert.ErrorIs(t, err, ErrDownload)
+	assert.False(t, HasDictionary(dir, dict))
+}
+
+func TestInstallDictionaryProgress(t *testing.T) {
+	dir := t.TempDir()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testContent))
+	}))
+	defer server.Close()
+
+	var done, total int64
+	opts := testOptions()
+	opts.Progress = func(d, t int64) { done, total = d, t }
+
+	_, err := InstallDictionary(dir, Dict{Language: "en", Name: "progress", URL: server.URL}, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(testContent)), done)
+	assert.Equal(t, int64(len(testContent)), total)
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	assert.Nil(t, VerifyChecksum("abc", ""))
+	assert.Nil(t, VerifyChecksum("abc", "abc"))
+	assert.Nil(t, VerifyChecksum("abc", "sha256:ABC"))
+	assert.ErrorIs(t, VerifyChecksum("abc", "def"), ErrChecksum)
+}
+
+func assertNoTempFiles(t *testing.T, dir string) {
+	entries, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	for _, e := range entries {
+		assert.NotContains(t, e.Name(), ".download-")
+	}
+}
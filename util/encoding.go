@@ -0,0 +1,79 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// ErrEncoding is an error for unknown text encodings
+var ErrEncoding = errors.New("unknown encoding")
+
+// Names of text encodings
+const (
+	EncodingUTF8        = "utf-8"
+	EncodingWindows1252 = "windows-1252"
+)
+
+var utf8BOM = []byte{0xef, 0xbb, 0xbf}
+
+// legacyEncodings are the supported single-byte encodings, with aliases
+var legacyEncodings = map[string]*charmap.Charmap{
+	"latin-1":          charmap.ISO8859_1,
+	"latin1":           charmap.ISO8859_1,
+	"iso-8859-1":       charmap.ISO8859_1,
+	"iso8859-1":        charmap.ISO8859_1,
+	"latin-2":          charmap.ISO8859_2,
+	"iso-8859-2":       charmap.ISO8859_2,
+	"iso8859-2":        charmap.ISO8859_2,
+	"latin-9":          charmap.ISO8859_15,
+	"latin9":           charmap.ISO8859_15,
+	"iso-8859-15":      charmap.ISO8859_15,
+	"iso8859-15":       charmap.ISO8859_15,
+	"windows-1251":     charmap.Windows1251,
+	"cp1251":           charmap.Windows1251,
+	"microsoft-cp1251": charmap.Windows1251,
+	"windows-1252":     charmap.Windows1252,
+	"cp1252":           charmap.Windows1252,
+	"koi8-r":           charmap.KOI8R,
+}
+
+// Encodings returns the names of all supported encodings, including aliases
+func Encodings() []string {
+	names := append(maps.Keys(legacyEncodings), EncodingUTF8, "auto")
+	sort.Strings(names)
+	return names
+}
+
+// DetectEncoding detects the encoding of text.
+// Returns utf-8 for valid UTF-8, and windows-1252 otherwise.
+// Windows-1252 is a superset of the printable characters of Latin-1.
+func DetectEncoding(content []byte) string {
+	if utf8.Valid(content) {
+		return EncodingUTF8
+	}
+	return EncodingWindows1252
+}
+
+// Decode converts text to UTF-8. Detects the encoding if encoding is empty or "auto".
+// Removes a UTF-8 byte order mark.
+func Decode(content []byte, encoding string) ([]byte, error) {
+	enc := strings.ToLower(strings.TrimSpace(encoding))
+	if enc == "" || enc == "auto" {
+		enc = DetectEncoding(content)
+	}
+	if enc == EncodingUTF8 || enc == "utf8" {
+		return bytes.TrimPrefix(content, utf8BOM), nil
+	}
+	cm, ok := legacyEncodings[enc]
+	if !ok {
+		return nil, fmt.Errorf("%w '%s'. Supported: %s", ErrEncoding, encoding, strings.Join(Encodings(), ", "))
+	}
+	return cm.NewDecoder().Bytes(content)
+}
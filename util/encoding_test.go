@@ -0,0 +1,40 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode(t *testing.T) {
+	tt := []struct {
+		title    string
+		content  []byte
+		encoding string
+		expected string
+		err      error
+	}{
+		{title: "utf-8", content: []byte("Möwe\n"), expected: "Möwe\n"},
+		{title: "utf-8 BOM", content: []byte("\xef\xbb\xbfMöwe\n"), encoding: "utf-8", expected: "Möwe\n"},
+		{title: "latin-1 detected", content: []byte("M\xf6we\nStra\xdfe\n"), expected: "Möwe\nStraße\n"},
+		{title: "windows-1252 detected", content: []byte("\x93quoted\x94\n"), expected: "“quoted”\n"},
+		{title: "latin-1 declared", content: []byte("M\xf6we\n"), encoding: "Latin-1", expected: "Möwe\n"},
+		{title: "latin-9 declared", content: []byte("\xa4uro\n"), encoding: "iso-8859-15", expected: "€uro\n"},
+		{title: "unknown", content: []byte("abc"), encoding: "ebcdic", err: ErrEncoding},
+	}
+
+	for _, test := range tt {
+		result, err := Decode(test.content, test.encoding)
+		if test.err != nil {
+			assert.ErrorIs(t, err, test.err, "Expected error in %s", test.title)
+			continue
+		}
+		assert.Nil(t, err, "Unexpected error in %s", test.title)
+		assert.Equal(t, test.expected, string(result), "Wrong result in %s", test.title)
+	}
+}
+
+func TestDetectEncoding(t *testing.T) {
+	assert.Equal(t, EncodingUTF8, DetectEncoding([]byte("Möwe")))
+	assert.Equal(t, EncodingWindows1252, DetectEncoding([]byte("M\xf6we")))
+}
@@ -0,0 +1,133 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrFilter is an error for invalid load filters
+var ErrFilter = errors.New("invalid filter")
+
+// LoadFilter selects the words of a dictionary that are loaded
+type LoadFilter struct {
+	// Lowercase excludes words containing upper-case letters
+	Lowercase bool `yaml:"lowercase,omitempty"`
+	// MinLength is the minimum word length. 0 means no limit
+	MinLength int `yaml:"min-length,omitempty"`
+	// MaxLength is the maximum word length. 0 means no limit
+	MaxLength int `yaml:"max-length,omitempty"`
+	// NoDigits excludes words containing digits
+	NoDigits bool `yaml:"no-digits,omitempty"`
+	// NoApostrophes excludes words containing apostrophes
+	NoApostrophes bool `yaml:"no-apostrophes,omitempty"`
+	// Exclude is a regular expression for words to exclude
+	Exclude string `yaml:"exclude,omitempty"`
+}
+
+// IsEmpty returns whether the filter lets all words pass
+func (f *LoadFilter) IsEmpty() bool {
+	return *f == LoadFilter{}
+}
+
+// Merge combines two filters. The result only lets words pass that pass both filters.
+func (f LoadFilter) Merge(other LoadFilter) LoadFilter {
+	f.Lowercase = f.Lowercase || other.Lowercase
+	f.NoDigits = f.NoDigits || other.NoDigits
+	f.NoApostrophes = f.NoApostrophes || other.NoApostrophes
+	if other.MinLength > f.MinLength {
+		f.MinLength = other.MinLength
+	}
+	if other.MaxLength > 0 && (f.MaxLength == 0 || other.MaxLength < f.MaxLength) {
+		f.MaxLength = other.MaxLength
+	}
+	switch {
+	case f.Exclude == "":
+		f.Exclude = other.Exclude
+	case other.Exclude != "" && other.Exclude != f.Exclude:
+		f.Exclude = fmt.Sprintf("(?:%s)|(?:%s)", f.Exclude, other.Exclude)
+	}
+	return f
+}
+
+// Validate checks the filter for invalid lengths and patterns
+func (f *LoadFilter) Validate() error {
+	if f.MinLength < 0 || f.MaxLength < 0 {
+		return fmt.Errorf("%w: negative word length", ErrFilter)
+	}
+	if f.MaxLength > 0 && f.MinLength > f.MaxLength {
+		return fmt.Errorf("%w: minimum length %d is larger than maximum length %d", ErrFilter, f.MinLength, f.MaxLength)
+	}
+	if _, err := regexp.Compile(f.Exclude); err != nil {
+		return fmt.Errorf("%w: exclude pattern: %s", ErrFilter, err)
+	}
+	return nil
+}
+
+// Apply returns the words that pass the filter
+func (f *LoadFilter) Apply(words []string) ([]string, error) {
+	if f.IsEmpty() {
+		return words, nil
+	}
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	var exclude *regexp.Regexp
+	if f.Exclude != "" {
+		exclude = regexp.MustCompile(f.Exclude)
+	}
+
+	result := make([]string, 0, len(words))
+	for _, word := range words {
+		if word == "" {
+			continue
+		}
+		length := utf8.RuneCountInString(word)
+		if length < f.MinLength || (f.MaxLength > 0 && length > f.MaxLength) {
+			continue
+		}
+		if f.Lowercase && strings.IndexFunc(word, unicode.IsUpper) >= 0 {
+			continue
+		}
+		if f.NoDigits && strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+			continue
+		}
+		if f.NoApostrophes && strings.ContainsAny(word, "'’") {
+			continue
+		}
+		if exclude != nil && exclude.MatchString(word) {
+			continue
+		}
+		result = append(result, word)
+	}
+	return result, nil
+}
+
+// ParseLengthRange parses a range of word lengths like "3-15", "3-" or "-15".
+// Returns 0 for missing bounds.
+func ParseLengthRange(lengths string) (int, int, error) {
+	parts := strings.Split(lengths, "-")
+	if len(parts) > 2 {
+		return 0, 0, fmt.Errorf("%w: invalid length range '%s'", ErrFilter, lengths)
+	}
+	values := make([]int, len(parts))
+	for i, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		val, err := strconv.Atoi(p)
+		if err != nil || val < 0 {
+			return 0, 0, fmt.Errorf("%w: invalid length range '%s'", ErrFilter, lengths)
+		}
+		values[i] = val
+	}
+	if len(values) == 1 {
+		return values[0], values[0], nil
+	}
+	return values[0], values[1], nil
+}
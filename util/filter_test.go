@@ -0,0 +1,71 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFilter(t *testing.T) {
+	words := []string{"abc", "Name", "abcdefgh", "x1", "don't", "it’s", "xyz", ""}
+
+	tt := []struct {
+		title    string
+		filter   LoadFilter
+		expected []string
+	}{
+		{title: "empty", filter: LoadFilter{}, expected: words},
+		{title: "lowercase", filter: LoadFilter{Lowercase: true}, expected: []string{"abc", "abcdefgh", "x1", "don't", "it’s", "xyz"}},
+		{title: "lengths", filter: LoadFilter{MinLength: 3, MaxLength: 5}, expected: []string{"abc", "Name", "don't", "it’s", "xyz"}},
+		{title: "no digits", filter: LoadFilter{NoDigits: true}, expected: []string{"abc", "Name", "abcdefgh", "don't", "it’s", "xyz"}},
+		{title: "no apostrophes", filter: LoadFilter{NoApostrophes: true}, expected: []string{"abc", "Name", "abcdefgh", "x1", "xyz"}},
+		{title: "exclude", filter: LoadFilter{Exclude: "^x"}, expected: []string{"abc", "Name", "abcdefgh", "don't", "it’s"}},
+	}
+
+	for _, test := range tt {
+		result, err := test.filter.Apply(words)
+		assert.Nil(t, err, "Unexpected error in %s", test.title)
+		assert.Equal(t, test.expected, result, "Wrong words in %s", test.title)
+	}
+
+	_, err := (&LoadFilter{Exclude: "["}).Apply(words)
+	assert.ErrorIs(t, err, ErrFilter)
+	_, err = (&LoadFilter{MinLength: 5, MaxLength: 3}).Apply(words)
+	assert.ErrorIs(t, err, ErrFilter)
+}
+
+func TestLoadFilterMerge(t *testing.T) {
+	a := LoadFilter{Lowercase: true, MinLength: 3, MaxLength: 10, Exclude: "^x"}
+	b := LoadFilter{NoDigits: true, MinLength: 2, MaxLength: 8, Exclude: "y$"}
+
+	assert.Equal(t,
+		LoadFilter{Lowercase: true, NoDigits: true, MinLength: 3, MaxLength: 8, Exclude: "(?:^x)|(?:y$)"},
+		a.Merge(b))
+	assert.Equal(t, a, a.Merge(LoadFilter{}))
+	assert.Equal(t, a, LoadFilter{}.Merge(a))
+}
+
+func TestParseLengthRange(t *testing.T) {
+	tt := []struct {
+		lengths  string
+		min, max int
+		err      bool
+	}{
+		{lengths: "3-15", min: 3, max: 15},
+		{lengths: "3-", min: 3, max: 0},
+		{lengths: "-15", min: 0, max: 15},
+		{lengths: "5", min: 5, max: 5},
+		{lengths: "a-5", err: true},
+		{lengths: "1-2-3", err: true},
+	}
+
+	for _, test := range tt {
+		min, max, err := ParseLengthRange(test.lengths)
+		if test.err {
+			assert.ErrorIs(t, err, ErrFilter, "Expected error for %s", test.lengths)
+			continue
+		}
+		assert.Nil(t, err, "Unexpected error for %s", test.lengths)
+		assert.Equal(t, []int{test.min, test.max}, []int{min, max}, "Wrong range for %s", test.lengths)
+	}
+}
@@ -0,0 +1,124 @@
+package util
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Metadata holds information about an installed dictionary.
+// Metadata is stored in a sidecar file next to the dictionary, and is updated whenever the dictionary is written.
+type Metadata struct {
+	// Source is the URL or path the dictionary was installed from
+	Source      string `yaml:"source,omitempty"`
+	License     string `yaml:"license,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	// Installed is the time the dictionary was first installed
+	Installed time.Time `yaml:"installed"`
+	// Updated is the time the dictionary was last written
+	Updated time.Time `yaml:"updated"`
+	// Words is the number of words, without the overlay
+	Words int `yaml:"words"`
+	// Checksum is the SHA-256 checksum of the unpacked word list, prefixed with "sha256:"
+	Checksum string `yaml:"checksum"`
+	// Encoding is the original encoding of the source
+	Encoding string `yaml:"encoding,omitempty"`
+	// File is the word list inside the source archive, if any
+	File string `yaml:"file,omitempty"`
+	// Alphabet holds all letters used in the dictionary, in lower case
+	Alphabet string `yaml:"alphabet"`
+}
+
+// LoadMetadata loads the metadata of a dictionary. Returns false if there is no metadata.
+// For embedded starter dictionaries, the metadata is derived from the content.
+func LoadMetadata(root string, dict Dict) (Metadata, bool, error) {
+	path := MetadataPath(root, dict)
+	if !FileExists(path) {
+		if isEmbedded(root, dict) {
+			meta, err := starterMetadata(dict)
+			return meta, err == nil, err
+		}
+		return Metadata{}, false, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Metadata{}, false, err
+	}
+	var meta Metadata
+	if err := yaml.Unmarshal(content, &meta); err != nil {
+		return Metadata{}, false, fmt.Errorf("invalid metadata file %s: %s", path, err)
+	}
+	return meta, true, nil
+}
+
+// SaveMetadata saves the metadata of a dictionary
+func SaveMetadata(root string, dict Dict, meta Metadata) error {
+	content, err := yaml.Marshal(&meta)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(MetadataPath(root, dict), content, 0644)
+}
+
+// updateMetadata updates the metadata of a dictionary for newly written content.
+// Source information is taken from the dict, and kept from existing metadata if not given.
+func updateMetadata(root string, dict Dict, content []byte) error {
+	meta, _, err := LoadMetadata(root, dict)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	if meta.Installed.IsZero() {
+		meta.Installed = now
+	}
+	meta.Updated = now
+
+	if dict.URL != "" {
+		meta.Source = dict.URL
+	}
+	if dict.License != "" {
+		meta.License = dict.License
+	}
+	if dict.Description != "" {
+		meta.Description = dict.Description
+	}
+	if dict.Encoding != "" {
+		meta.Encoding = dict.Encoding
+	}
+	if dict.File != "" {
+		meta.File = dict.File
+	}
+
+	meta.Words = CountWords(content)
+	meta.Checksum = Checksum(content)
+	meta.Alphabet = Alphabet(splitWords(content))
+	return SaveMetadata(root, dict, meta)
+}
+
+// Checksum calculates the SHA-256 checksum of content, prefixed with "sha256:"
+func Checksum(content []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+}
+
+// Alphabet returns all letters used in the words, in lower case and sorted
+func Alphabet(words []string) string {
+	letters := map[rune]bool{}
+	for _, word := range words {
+		for _, r := range word {
+			if unicode.IsLetter(r) {
+				letters[unicode.ToLower(r)] = true
+			}
+		}
+	}
+	runes := make([]rune, 0, len(letters))
+	for r := range letters {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return string(runes)
+}
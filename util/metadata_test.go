@@ -0,0 +1,51 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlphabet(t *testing.T) {
+	assert.Equal(t, "", Alphabet([]string{}))
+	assert.Equal(t, "abc", Alphabet([]string{"cab", "Ab"}))
+	assert.Equal(t, "aelnßö", Alphabet([]string{"Öl", "naß", "e-l'an"}))
+}
+
+func TestMetadata(t *testing.T) {
+	dir := t.TempDir()
+
+	source := filepath.Join(dir, "words.txt")
+	assert.Nil(t, os.WriteFile(source, []byte("one\ntwo\n"), 0644))
+
+	dict := Dict{Language: "en", Name: "words", URL: source, License: "CC0"}
+	_, err := InstallDictionary(dir, dict, DefaultDownloadOptions())
+	assert.Nil(t, err)
+
+	meta, ok, err := LoadMetadata(dir, dict)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, source, meta.Source)
+	assert.Equal(t, "CC0", meta.License)
+	assert.Equal(t, 2, meta.Words)
+	assert.Equal(t, Checksum([]byte("one\ntwo\n")), meta.Checksum)
+	assert.Equal(t, EncodingUTF8, meta.Encoding)
+	assert.Equal(t, "enotw", meta.Alphabet)
+	assert.False(t, meta.Installed.IsZero())
+
+	_, err = SaveDictionary(dir, NewDict("en/words"), []string{"three"})
+	assert.Nil(t, err)
+
+	updated, ok, err := LoadMetadata(dir, dict)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, source, updated.Source, "Source should be kept")
+	assert.Equal(t, meta.Installed, updated.Installed, "Installation time should be kept")
+	assert.Equal(t, 1, updated.Words)
+
+	_, ok, err = LoadMetadata(dir, NewDict("en/missing"))
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
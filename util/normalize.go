@@ -0,0 +1,103 @@
+package util
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Kinds of issues found in word lists
+const (
+	IssueCarriageReturn = "carriage return"
+	IssueWhitespace     = "leading or trailing whitespace"
+	IssueEmpty          = "empty line"
+	IssueComment        = "comment"
+	IssueDuplicate      = "duplicate"
+)
+
+// commentPrefix marks comment lines in word lists
+const commentPrefix = "#"
+
+// Issue is a problem in a word list
+type Issue struct {
+	Line int
+	Kind string
+	Text string
+}
+
+// CheckWords finds problems in the content of a word list: carriage returns (CRLF line endings),
+// leading or trailing whitespace, empty lines, comment lines and duplicate words.
+// Line numbers start at 1.
+func CheckWords(content []byte) []Issue {
+	issues := []Issue{}
+	seen := map[string]bool{}
+	for i, line := range splitLines(content) {
+		word := strings.TrimSpace(line)
+		switch {
+		case word == "":
+			issues = append(issues, Issue{Line: i + 1, Kind: IssueEmpty, Text: line})
+			continue
+		case strings.HasPrefix(word, commentPrefix):
+			issues = append(issues, Issue{Line: i + 1, Kind: IssueComment, Text: line})
+			continue
+		case strings.HasSuffix(line, "\r"):
+			issues = append(issues, Issue{Line: i + 1, Kind: IssueCarriageReturn, Text: line})
+			if strings.TrimRight(line, "\r") != word {
+				issues = append(issues, Issue{Line: i + 1, Kind: IssueWhitespace, Text: line})
+			}
+		case line != word:
+			issues = append(issues, Issue{Line: i + 1, Kind: IssueWhitespace, Text: line})
+		}
+		if seen[word] {
+			issues = append(issues, Issue{Line: i + 1, Kind: IssueDuplicate, Text: line})
+		}
+		seen[word] = true
+	}
+	return issues
+}
+
+// Normalize fixes the problems reported by CheckWords.
+// Strips whitespace and carriage returns, and removes empty lines, comments and duplicates.
+// The result has one word per line, terminated by a newline.
+func Normalize(content []byte) []byte {
+	buf := bytes.Buffer{}
+	buf.Grow(len(content))
+	seen := map[string]bool{}
+	for _, line := range splitLines(content) {
+		word := strings.TrimSpace(line)
+		if word == "" || strings.HasPrefix(word, commentPrefix) || seen[word] {
+			continue
+		}
+		seen[word] = true
+		buf.WriteString(word)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// NormalizeDictionary normalizes an installed dictionary in place, keeping its storage format.
+// Returns the number of issues fixed.
+func NormalizeDictionary(root string, dict Dict) (int, error) {
+	content, err := ReadDictionary(root, dict)
+	if err != nil {
+		return 0, err
+	}
+	issues := CheckWords(content)
+	if len(issues) == 0 {
+		return 0, nil
+	}
+	path, _ := InstalledDictPath(root, dict)
+	if err := writeDictionary(root, dict, Normalize(content), path == CompressedDictPath(root, dict)); err != nil {
+		return 0, err
+	}
+	return len(issues), nil
+}
+
+// splitLines splits content into lines. A final line break does not produce an empty line.
+func splitLines(content []byte) []string {
+	text := string(content)
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(text, "\n")
+}
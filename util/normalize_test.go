@@ -0,0 +1,34 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckWords(t *testing.T) {
+	content := []byte("alpha\r\n beta\n\n# comment\ngamma \r\nalpha\r\ndelta\n")
+	issues := CheckWords(content)
+
+	assert.Equal(t, []Issue{
+		{Line: 1, Kind: IssueCarriageReturn, Text: "alpha\r"},
+		{Line: 2, Kind: IssueWhitespace, Text: " beta"},
+		{Line: 3, Kind: IssueEmpty, Text: ""},
+		{Line: 4, Kind: IssueComment, Text: "# comment"},
+		{Line: 5, Kind: IssueCarriageReturn, Text: "gamma \r"},
+		{Line: 5, Kind: IssueWhitespace, Text: "gamma \r"},
+		{Line: 6, Kind: IssueCarriageReturn, Text: "alpha\r"},
+		{Line: 6, Kind: IssueDuplicate, Text: "alpha\r"},
+	}, issues)
+
+	assert.Empty(t, CheckWords([]byte("alpha\nbeta\n")))
+	assert.Empty(t, CheckWords([]byte("alpha\nbeta")))
+}
+
+func TestNormalize(t *testing.T) {
+	content := []byte("alpha\r\n beta\n\n# comment\ngamma \r\nalpha\r\ndelta")
+	normalized := Normalize(content)
+
+	assert.Equal(t, "alpha\nbeta\ngamma\ndelta\n", string(normalized))
+	assert.Empty(t, CheckWords(normalized))
+}
@@ -0,0 +1,155 @@
+package util
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Overlay holds user changes to a dictionary: added and blocked words.
+// Overlays are stored next to the dictionary, and applied whenever it is loaded.
+type Overlay struct {
+	Add   []string `yaml:"add,omitempty"`
+	Block []string `yaml:"block,omitempty"`
+}
+
+// LoadOverlay loads the overlay of a dictionary. Returns an empty overlay if there is none.
+func LoadOverlay(root string, dict Dict) (Overlay, error) {
+	path := OverlayPath(root, dict)
+	if !FileExists(path) {
+		return Overlay{}, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Overlay{}, err
+	}
+	var overlay Overlay
+	if err := yaml.Unmarshal(content, &overlay); err != nil {
+		return Overlay{}, fmt.Errorf("invalid overlay file %s: %s", path, err)
+	}
+	return overlay, nil
+}
+
+// SaveOverlay saves the overlay of a dictionary. Removes the overlay file if the overlay is empty.
+func SaveOverlay(root string, dict Dict, overlay Overlay) error {
+	path := OverlayPath(root, dict)
+	if overlay.IsEmpty() {
+		if FileExists(path) {
+			return os.Remove(path)
+		}
+		return nil
+	}
+	content, err := yaml.Marshal(&overlay)
+	if err != nil {
+		return err
+	}
+	if err := CreateDir(LanguageDir(root, dict.Language)); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// IsEmpty returns whether the overlay has no changes
+func (o *Overlay) IsEmpty() bool {
+	return len(o.Add) == 0 && len(o.Block) == 0
+}
+
+// AddWords adds words, and un-blocks them if they were blocked.
+// Returns the words that were not added before.
+func (o *Overlay) AddWords(words ...string) []string {
+	o.Block, _ = without(o.Block, words)
+	var added []string
+	o.Add, added = with(o.Add, words)
+	return added
+}
+
+// BlockWords blocks words, and un-adds them if they were added.
+// Returns the words that were not blocked before.
+func (o *Overlay) BlockWords(words ...string) []string {
+	o.Add, _ = without(o.Add, words)
+	var blocked []string
+	o.Block, blocked = with(o.Block, words)
+	return blocked
+}
+
+// RemoveAdded removes words from the added words. Returns the removed words
+func (o *Overlay) RemoveAdded(words ...string) []string {
+	var removed []string
+	o.Add, removed = without(o.Add, words)
+	return removed
+}
+
+// RemoveBlocked removes words from the blocked words. Returns the removed words
+func (o *Overlay) RemoveBlocked(words ...string) []string {
+	var removed []string
+	o.Block, removed = without(o.Block, words)
+	return removed
+}
+
+// ResetWords removes words from the added and blocked words
+func (o *Overlay) ResetWords(words ...string) {
+	o.Add, _ = without(o.Add, words)
+	o.Block, _ = without(o.Block, words)
+}
+
+// Apply removes blocked words from a word list, and appends added words that are not in the list yet
+func (o *Overlay) Apply(words []string) []string {
+	if o.IsEmpty() {
+		return words
+	}
+	blocked := toSet(o.Block)
+	present := make(map[string]bool, len(words))
+
+	result := make([]string, 0, len(words)+len(o.Add))
+	for _, word := range words {
+		if blocked[word] {
+			continue
+		}
+		present[word] = true
+		result = append(result, word)
+	}
+	for _, word := range o.Add {
+		if !present[word] {
+			result = append(result, word)
+		}
+	}
+	return result
+}
+
+// with appends words not in the list yet. Returns the new list and the appended words
+func with(list []string, words []string) ([]string, []string) {
+	set := toSet(list)
+	appended := []string{}
+	for _, word := range words {
+		if !set[word] {
+			set[word] = true
+			list = append(list, word)
+			appended = append(appended, word)
+		}
+	}
+	return list, appended
+}
+
+// without removes words from the list. Returns the new list and the removed words
+func without(list []string, words []string) ([]string, []string) {
+	remove := toSet(words)
+	result := list[:0]
+	removed := []string{}
+	for _, word := range list {
+		if remove[word] {
+			removed = append(removed, word)
+		} else {
+			result = append(result, word)
+		}
+	}
+	return result, removed
+}
+
+func toSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
@@ -0,0 +1,65 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverlayWords(t *testing.T) {
+	overlay := Overlay{}
+	assert.True(t, overlay.IsEmpty())
+
+	overlay.AddWords("foo", "bar", "foo")
+	overlay.BlockWords("baz", "bar")
+	assert.Equal(t, []string{"foo"}, overlay.Add)
+	assert.Equal(t, []string{"baz", "bar"}, overlay.Block)
+
+	overlay.AddWords("bar")
+	assert.Equal(t, []string{"foo", "bar"}, overlay.Add)
+	assert.Equal(t, []string{"baz"}, overlay.Block)
+
+	overlay.ResetWords("foo", "bar", "baz")
+	assert.True(t, overlay.IsEmpty())
+
+	assert.Equal(t, []string{"foo", "bar"}, overlay.AddWords("foo", "bar", "foo"))
+	assert.Equal(t, []string{}, overlay.AddWords("bar"), "Expected no newly added words")
+	assert.Equal(t, []string{"baz"}, overlay.BlockWords("baz"))
+
+	assert.Equal(t, []string{"foo"}, overlay.RemoveAdded("foo", "baz"))
+	assert.Equal(t, []string{"bar"}, overlay.Add)
+	assert.Equal(t, []string{"baz"}, overlay.Block, "Removing added words should not un-block")
+
+	assert.Equal(t, []string{}, overlay.RemoveBlocked("bar"))
+	assert.Equal(t, []string{"baz"}, overlay.RemoveBlocked("baz"))
+	assert.Equal(t, []string{"bar"}, overlay.Add, "Removing blocked words should not un-add")
+}
+
+func TestOverlayApply(t *testing.T) {
+	overlay := Overlay{Add: []string{"new", "old"}, Block: []string{"bad"}}
+	words := overlay.Apply([]string{"old", "bad", "good"})
+	assert.Equal(t, []string{"old", "good", "new"}, words)
+}
+
+func TestOverlaySaveLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	dict := Dict{Language: "en", Name: "test"}
+	assert.Nil(t, CreateDir(LanguageDir(dir, dict.Language)))
+
+	overlay, err := LoadOverlay(dir, dict)
+	assert.Nil(t, err)
+	assert.True(t, overlay.IsEmpty())
+
+	overlay.AddWords("foo")
+	assert.Nil(t, SaveOverlay(dir, dict, overlay))
+	assert.True(t, FileExists(OverlayPath(dir, dict)))
+
+	loaded, err := LoadOverlay(dir, dict)
+	assert.Nil(t, err)
+	assert.Equal(t, overlay, loaded)
+
+	loaded.ResetWords("foo")
+	assert.Nil(t, SaveOverlay(dir, dict, loaded))
+	assert.False(t, FileExists(OverlayPath(dir, dict)))
+}
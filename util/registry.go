@@ -0,0 +1,110 @@
+package util
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed registry.yml
+var defaultRegistry []byte
+
+var registryNames = []string{"registry.yml", "registry.json"}
+
+// Registry lists dictionaries available for download
+type Registry struct {
+	Dictionaries []Dict `yaml:"dictionaries"`
+}
+
+// LoadRegistry loads the default registry, extended or overridden by the user's registry file
+func LoadRegistry(root string) (Registry, error) {
+	registry, err := ParseRegistry(defaultRegistry)
+	if err != nil {
+		return Registry{}, fmt.Errorf("invalid default registry: %s", err)
+	}
+
+	for _, name := range registryNames {
+		path := filepath.Join(root, name)
+		if !FileExists(path) {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return Registry{}, err
+		}
+		user, err := ParseRegistry(content)
+		if err != nil {
+			return Registry{}, fmt.Errorf("invalid registry file %s: %s", path, err)
+		}
+		registry.Merge(user)
+	}
+
+	return registry, nil
+}
+
+// ParseRegistry parses a registry from YAML or JSON
+func ParseRegistry(content []byte) (Registry, error) {
+	var registry Registry
+	if err := yaml.Unmarshal(content, &registry); err != nil {
+		return Registry{}, err
+	}
+	for i, dict := range registry.Dictionaries {
+		if dict.Name == "" || dict.Language == "" {
+			return Registry{}, fmt.Errorf("entry %d: name and language are required", i+1)
+		}
+		if dict.URL == "" {
+			return Registry{}, fmt.Errorf("entry %s: url is required", dict.FullName())
+		}
+	}
+	registry.sort()
+	return registry, nil
+}
+
+// Merge adds the dictionaries of another registry. Replaces entries with the same language and name
+func (r *Registry) Merge(other Registry) {
+	for _, dict := range other.Dictionaries {
+		if idx, ok := r.index(dict); ok {
+			r.Dictionaries[idx] = dict
+		} else {
+			r.Dictionaries = append(r.Dictionaries, dict)
+		}
+	}
+	r.sort()
+}
+
+// Find returns the registry entry with the language and name of the given dictionary
+func (r *Registry) Find(dict Dict) (Dict, bool) {
+	if idx, ok := r.index(dict); ok {
+		return r.Dictionaries[idx], true
+	}
+	return Dict{}, false
+}
+
+// HasLanguage checks if there are dictionaries in the given language
+func (r *Registry) HasLanguage(lang string) bool {
+	for _, dict := range r.Dictionaries {
+		if dict.Language == lang {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Registry) index(dict Dict) (int, bool) {
+	for i, d := range r.Dictionaries {
+		if d.Language == dict.Language && d.Name == dict.Name {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func (r *Registry) sort() {
+	sort.SliceStable(r.Dictionaries, func(i, j int) bool {
+		return r.Dictionaries[i].FullName() < r.Dictionaries[j].FullName()
+	})
+}
@@ -0,0 +1,62 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRegistry(t *testing.T) {
+	registry, err := ParseRegistry(defaultRegistry)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(registry.Dictionaries), "Wrong number of default dictionaries")
+
+	dict, ok := registry.Find(NewDict("en/yawl"))
+	assert.True(t, ok)
+	assert.Equal(t, "utf-8", dict.Encoding, "Wrong encoding")
+
+	registry, err = ParseRegistry([]byte(`{"dictionaries": [{"name": "test", "language": "fr", "url": "file:///test.txt"}]}`))
+	assert.Nil(t, err)
+	assert.Equal(t, []Dict{{Name: "test", Language: "fr", URL: "file:///test.txt"}}, registry.Dictionaries, "Wrong JSON registry")
+
+	_, err = ParseRegistry([]byte(`dictionaries: [{name: test, url: "file:///test.txt"}]`))
+	assert.NotNil(t, err, "Expected error for missing language")
+
+	_, err = ParseRegistry([]byte(`dictionaries: [{name: test, language: fr}]`))
+	assert.NotNil(t, err, "Expected error for missing URL")
+}
+
+func TestLoadRegistry(t *testing.T) {
+	dir := t.TempDir()
+
+	user := `
+dictionaries:
+  - name: yawl
+    language: en
+    url: https://example.com/yawl.txt
+    description: Overridden
+  - name: test
+    language: fr
+    url: https://example.com/test.txt
+`
+	err := os.WriteFile(filepath.Join(dir, "registry.yml"), []byte(user), 0644)
+	assert.Nil(t, err)
+
+	registry, err := LoadRegistry(dir)
+	assert.Nil(t, err)
+
+	names := []string{}
+	for _, d := range registry.Dictionaries {
+		names = append(names, d.FullName())
+	}
+	assert.Equal(t, []string{"de/enz", "en/yawl", "fr/test"}, names, "Wrong dictionaries in merged registry")
+
+	dict, ok := registry.Find(NewDict("en/yawl"))
+	assert.True(t, ok)
+	assert.Equal(t, "Overridden", dict.Description, "Entry not overridden")
+
+	assert.True(t, registry.HasLanguage("fr"))
+	assert.False(t, registry.HasLanguage("xx"))
+}
@@ -0,0 +1,75 @@
+package util
+
+import (
+	"embed"
+	"path"
+	"strings"
+)
+
+// StarterName is the name of the embedded starter dictionaries, like in 'en/starter'
+const StarterName = "starter"
+
+const starterDescription = "Starter dictionary, embedded in xwrd"
+
+//go:embed starter/*.lst
+var starterFiles embed.FS
+
+// StarterDictionaries returns the embedded starter dictionaries, one per supported language
+func StarterDictionaries() []Dict {
+	files, err := starterFiles.ReadDir("starter")
+	if err != nil {
+		panic(err)
+	}
+	dicts := make([]Dict, len(files))
+	for i, file := range files {
+		dicts[i] = Dict{
+			Language:    strings.TrimSuffix(file.Name(), dictExtension),
+			Name:        StarterName,
+			Description: starterDescription,
+		}
+	}
+	return dicts
+}
+
+// IsStarter checks if a dictionary is an embedded starter dictionary
+func IsStarter(dict Dict) bool {
+	if dict.Name != StarterName {
+		return false
+	}
+	_, err := starterFiles.Open(starterPath(dict))
+	return err == nil
+}
+
+// isEmbedded checks if a dictionary is served from the embedded starter dictionaries.
+// Starter dictionaries are not installed, but are always available without writing to the storage directory.
+// An installed copy, e.g. after changing a starter dictionary, takes precedence.
+func isEmbedded(root string, dict Dict) bool {
+	if _, ok := InstalledDictPath(root, dict); ok {
+		return false
+	}
+	return IsStarter(dict)
+}
+
+// readStarter reads the content of an embedded starter dictionary
+func readStarter(dict Dict) ([]byte, error) {
+	return starterFiles.ReadFile(starterPath(dict))
+}
+
+// starterMetadata creates the metadata of an embedded starter dictionary
+func starterMetadata(dict Dict) (Metadata, error) {
+	content, err := readStarter(dict)
+	if err != nil {
+		return Metadata{}, err
+	}
+	return Metadata{
+		Source:      "embedded",
+		Description: starterDescription,
+		Words:       CountWords(content),
+		Checksum:    Checksum(content),
+		Alphabet:    Alphabet(splitWords(content)),
+	}, nil
+}
+
+func starterPath(dict Dict) string {
+	return path.Join("starter", dict.Language+dictExtension)
+}
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStarterDictionaries(t *testing.T) {
+	dir := t.TempDir()
+
+	dicts := StarterDictionaries()
+	assert.Equal(t, []string{"de/starter", "en/starter"}, []string{dicts[0].FullName(), dicts[1].FullName()})
+	assert.True(t, IsStarter(NewDict("en/starter")))
+	assert.False(t, IsStarter(NewDict("xx/starter")))
+	assert.False(t, IsStarter(NewDict("en/yawl")))
+
+	for _, dict := range dicts {
+		assert.True(t, HasDictionary(dir, dict), "Starter dictionary %s not available", dict.FullName())
+		content, err := ReadDictionary(dir, dict)
+		assert.Nil(t, err)
+		assert.Empty(t, CheckWords(content), "Starter dictionary %s not normalized", dict.FullName())
+
+		meta, ok, err := LoadMetadata(dir, dict)
+		assert.Nil(t, err)
+		assert.True(t, ok, "No metadata for starter dictionary %s", dict.FullName())
+		assert.Equal(t, CountWords(content), meta.Words)
+	}
+	all, err := AllDictionaries(dir)
+	assert.Nil(t, err)
+	assert.Contains(t, all, "en/starter")
+
+	files, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Empty(t, files, "Starter dictionaries should not be written to the storage directory")
+
+	assert.ErrorIs(t, RemoveDictionary(dir, NewDict("en/starter")), ErrStarterDictionary)
+	assert.ErrorIs(t, RenameDictionary(dir, NewDict("en/starter"), NewDict("en/other")), ErrStarterDictionary)
+
+	_, err = SaveDictionary(dir, NewDict("en/starter"), []string{"changed"})
+	assert.Nil(t, err)
+	words, err := LoadDictionary(dir, NewDict("en/starter"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"changed"}, words, "Installed copy of starter dictionary should take precedence")
+}
@@ -0,0 +1,110 @@
+package util
+
+import (
+	"strings"
+)
+
+// LoadWordList loads words from a plain file, or from an installed dictionary if there is no file with the given name.
+// Empty lines and duplicates are removed.
+func LoadWordList(root string, source string) ([]string, error) {
+	words, _, err := LoadWordListFrequencies(root, source)
+	return words, err
+}
+
+// LoadWordListFrequencies loads words like LoadWordList.
+// Additionally, returns the word frequencies from the frequency column, if any.
+func LoadWordListFrequencies(root string, source string) ([]string, Frequencies, error) {
+	var words []string
+	var freqs Frequencies
+	if FileExists(source) {
+		content, err := ReadWordList(root, source)
+		if err != nil {
+			return nil, nil, err
+		}
+		words, freqs = splitEntries(content)
+	} else {
+		var err error
+		if words, freqs, err = LoadDictionaryFrequencies(root, NewDict(source)); err != nil {
+			return nil, nil, err
+		}
+	}
+	return Union(words), freqs, nil
+}
+
+// ReadWordList reads the unpacked content of a plain file, or of an installed dictionary if there is no file with the given name.
+// Content that is not UTF-8 encoded is converted. The overlay of installed dictionaries is not applied.
+func ReadWordList(root string, source string) ([]byte, error) {
+	if !FileExists(source) {
+		return ReadDictionary(root, NewDict(source))
+	}
+	return readText(source)
+}
+
+// SaveDictionary saves words as an installed dictionary. Returns the number of words.
+func SaveDictionary(root string, dict Dict, words []string) (int, error) {
+	return SaveDictionaryFrequencies(root, dict, words, nil)
+}
+
+// SaveDictionaryFrequencies saves words as an installed dictionary, like SaveDictionary.
+// Words with a frequency are written with a tab-separated frequency column.
+func SaveDictionaryFrequencies(root string, dict Dict, words []string, freqs Frequencies) (int, error) {
+	if err := CreateDir(LanguageDir(root, dict.Language)); err != nil {
+		return 0, err
+	}
+	lines := words
+	if len(freqs) > 0 {
+		lines = make([]string, len(words))
+		for i, word := range words {
+			lines[i] = word
+			if freq, ok := freqs[word]; ok {
+				lines[i] = WordCount{Word: word, Count: freq}.String()
+			}
+		}
+	}
+	content := strings.Join(lines, "\n") + "\n"
+	if err := writeDictionary(root, dict, []byte(content), false); err != nil {
+		return 0, err
+	}
+	return len(words), nil
+}
+
+// Union returns all words contained in any of the lists, in order of their first occurrence.
+// Empty words and duplicates are removed.
+func Union(lists ...[]string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, list := range lists {
+		for _, word := range list {
+			if word == "" || seen[word] {
+				continue
+			}
+			seen[word] = true
+			result = append(result, word)
+		}
+	}
+	return result
+}
+
+// Intersection returns the words of list a that are also contained in list b
+func Intersection(a, b []string) []string {
+	other := toSet(b)
+	result := []string{}
+	for _, word := range Union(a) {
+		if other[word] {
+			result = append(result, word)
+		}
+	}
+	return result
+}
+
+// Difference returns the words of list a that are not contained in list b
+func Difference(a, b []string) []string {
+	other := toSet(b)
+	result := []string{}
+	for _, word := range Union(a) {
+		if !other[word] {
+			result = append(result, word)
+		}
+	}
+	return result
+}
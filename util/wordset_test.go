@@ -0,0 +1,40 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWordSetOperations(t *testing.T) {
+	a := []string{"one", "two", "", "three", "two"}
+	b := []string{"three", "four", "one"}
+
+	assert.Equal(t, []string{"one", "two", "three", "four"}, Union(a, b))
+	assert.Equal(t, []string{"one", "three"}, Intersection(a, b))
+	assert.Equal(t, []string{"two"}, Difference(a, b))
+	assert.Equal(t, []string{"four"}, Difference(b, a))
+}
+
+func TestSaveDictionaryFrequencies(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "words.txt")
+	assert.Nil(t, os.WriteFile(path, []byte("the\t4\nfox\t2\nden\n"), 0644))
+
+	words, freqs, err := LoadWordListFrequencies(dir, path)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"the", "fox", "den"}, words)
+	assert.Equal(t, Frequencies{"the": 4, "fox": 2}, freqs)
+
+	dict := NewDict("en/result")
+	count, err := SaveDictionaryFrequencies(dir, dict, []string{"fox", "den"}, freqs)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	content, err := ReadDictionary(dir, dict)
+	assert.Nil(t, err)
+	assert.Equal(t, "fox\t2\nden\n", string(content))
+}